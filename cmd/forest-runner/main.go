@@ -18,7 +18,7 @@ ARCHITECTURE INTEGRATION:
 
 ERROR HANDLING:
   - Top-level panic recovery (though discouraged in favor of explicit error handling).
-  - Explicit error check on Execute(); exit code 1 on failure.
+  - Explicit error check on Execute(); exit code from cli.ExitCode (1, or 130 on interrupt).
 
 IMPLEMENTATION RULES:
   - Critical: Keep main() minimal. All logic belongs in internal/ packages.
@@ -52,6 +52,6 @@ import (
 func main() {
 	if err := cli.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
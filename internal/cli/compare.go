@@ -0,0 +1,224 @@
+/*
+PURPOSE:
+  Defines the 'compare' subcommand.
+  Diffs two JSON Lines results files (before/after tuning) per (model, config).
+
+REQUIREMENTS:
+  User-specified:
+  - Join on (model, config); report % change in tokens/sec and latency.
+  - Flag regressions beyond --threshold percent; exit non-zero if any exist (CI gate).
+  - Green improvements, red regressions, --no-color to disable.
+  - Models present in only one file are listed as added/removed.
+
+  Implementation-discovered:
+  - Repeat runs and multiple URLs produce several rows per key; they are averaged.
+  - Failed rows carry no metrics and are ignored.
+  - NO_COLOR (https://no-color.org) is honored as well as --no-color.
+  - prompt_dir runs also key on the prompt name, shown as "model [prompt]".
+  - A change from zero (e.g. every old run reported 0 tok/s) is ±Inf%, so it
+    still trips --threshold instead of reading as "no change".
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/output.ReadResults(), internal/model stats helpers
+
+ERROR HANDLING:
+  - Returns error on unreadable files.
+  - Returns error (exit 1) when any regression exceeds the threshold.
+
+IMPLEMENTATION RULES:
+  - Read-only; never modifies either results file.
+
+USAGE:
+  forest-runner compare before/model_results.json after/model_results.json --threshold 10
+
+SELF-HEALING INSTRUCTIONS:
+  - If nothing joins, check that both runs used identical inference_configs.
+
+RELATED FILES:
+  - internal/cli/report.go
+  - internal/output/reader.go
+
+MAINTENANCE:
+  - Keep the join key in sync with internal/engine/resume.go.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiReset = "\033[0m"
+)
+
+var (
+	compareThreshold float64
+	compareNoColor   bool
+)
+
+// compareStat is the averaged performance of one (model, config) key in one file.
+type compareStat struct {
+	Model   string
+	Config  string
+	TPS     float64
+	Latency float64 // seconds
+}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <old.json> <new.json>",
+	Short: "Compare two results files and flag regressions",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldResults, err := output.ReadResults(args[0])
+		if err != nil {
+			return err
+		}
+		newResults, err := output.ReadResults(args[1])
+		if err != nil {
+			return err
+		}
+
+		before := summarizeByKey(oldResults)
+		after := summarizeByKey(newResults)
+
+		keys := make([]string, 0, len(before)+len(after))
+		for k := range before {
+			keys = append(keys, k)
+		}
+		for k := range after {
+			if _, ok := before[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		color := !compareNoColor && os.Getenv("NO_COLOR") == ""
+		var buf bytes.Buffer
+		tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Model\tConfig\tOld Tk/s\tNew Tk/s\tΔ Tk/s\tOld Lat(s)\tNew Lat(s)\tΔ Lat\tStatus")
+		fmt.Fprintln(tw, "-----\t------\t--------\t--------\t------\t----------\t----------\t-----\t------")
+
+		// One status per printed row, used to colorize after tabwriter aligns the columns.
+		statuses := []string{"", ""}
+		regressions := 0
+		for _, k := range keys {
+			o, inOld := before[k]
+			n, inNew := after[k]
+			switch {
+			case !inNew:
+				fmt.Fprintf(tw, "%s\t%s\t%.1f\t-\t-\t%.3f\t-\t-\tremoved\n", o.Model, o.Config, o.TPS, o.Latency)
+				statuses = append(statuses, "removed")
+			case !inOld:
+				fmt.Fprintf(tw, "%s\t%s\t-\t%.1f\t-\t-\t%.3f\t-\tadded\n", n.Model, n.Config, n.TPS, n.Latency)
+				statuses = append(statuses, "added")
+			default:
+				tpsDelta := percentChange(o.TPS, n.TPS)
+				latDelta := percentChange(o.Latency, n.Latency)
+
+				status := compareStatus(tpsDelta, latDelta, compareThreshold)
+				if status == "REGRESSION" {
+					regressions++
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%.1f\t%.1f\t%+.1f%%\t%.3f\t%.3f\t%+.1f%%\t%s\n",
+					n.Model, n.Config, o.TPS, n.TPS, tpsDelta, o.Latency, n.Latency, latDelta, status)
+				statuses = append(statuses, status)
+			}
+		}
+		tw.Flush()
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		for i, line := range lines {
+			if color && i < len(statuses) {
+				switch statuses[i] {
+				case "REGRESSION":
+					line = ansiRed + line + ansiReset
+				case "improved":
+					line = ansiGreen + line + ansiReset
+				}
+			}
+			fmt.Println(line)
+		}
+
+		if regressions > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d regression(s) exceed the %.1f%% threshold", regressions, compareThreshold)
+		}
+		return nil
+	},
+}
+
+// summarizeByKey averages successful results per (model, prompt, config).
+func summarizeByKey(results []model.Result) map[string]compareStat {
+	tps := make(map[string][]float64)
+	latency := make(map[string][]float64)
+	stats := make(map[string]compareStat)
+
+	for _, r := range results {
+		if r.Error != "" {
+			continue
+		}
+		cfgBytes, _ := json.Marshal(r.Config) // sorted keys, same canonical form as --resume
+		k := r.Model + "\x00" + r.PromptName + "\x00" + string(cfgBytes)
+		name := r.Model
+		if r.PromptName != "" {
+			name += " [" + r.PromptName + "]"
+		}
+		stats[k] = compareStat{Model: name, Config: string(cfgBytes)}
+		tps[k] = append(tps[k], r.TokensPerSecond)
+		latency[k] = append(latency[k], r.Duration.Seconds())
+	}
+
+	for k, s := range stats {
+		s.TPS = model.ComputeStats(tps[k]).Mean
+		s.Latency = model.ComputeStats(latency[k]).Mean
+		stats[k] = s
+	}
+	return stats
+}
+
+// percentChange returns the relative change from old to new in percent.
+// A change from zero has no finite percentage and is reported as ±Inf.
+func percentChange(old, new float64) float64 {
+	if old == 0 {
+		switch {
+		case new > 0:
+			return math.Inf(1)
+		case new < 0:
+			return math.Inf(-1)
+		}
+		return 0
+	}
+	return (new - old) / old * 100
+}
+
+// compareStatus classifies a row: slower tokens/sec or higher latency beyond
+// threshold percent is a regression, the reverse an improvement.
+func compareStatus(tpsDelta, latDelta, threshold float64) string {
+	switch {
+	case tpsDelta < -threshold || latDelta > threshold:
+		return "REGRESSION"
+	case tpsDelta > threshold || latDelta < -threshold:
+		return "improved"
+	}
+	return "ok"
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().Float64Var(&compareThreshold, "threshold", 5, "Percent change in tokens/sec or latency counted as a regression")
+	compareCmd.Flags().BoolVar(&compareNoColor, "no-color", false, "Disable colored output")
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new float64
+		want     float64
+	}{
+		{name: "unchanged", old: 50, new: 50, want: 0},
+		{name: "faster", old: 50, new: 75, want: 50},
+		{name: "slower", old: 50, new: 25, want: -50},
+		{name: "dropped to zero", old: 50, new: 0, want: -100},
+		{name: "from zero", old: 0, new: 40, want: math.Inf(1)},
+		{name: "zero to zero", old: 0, new: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentChange(tt.old, tt.new); got != tt.want {
+				t.Fatalf("percentChange(%v, %v) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareStatusFromZero(t *testing.T) {
+	// Old latency 0 (e.g. a broken baseline) must not hide a new one as "no change"
+	if got := compareStatus(0, percentChange(0, 1.5), 10); got != "REGRESSION" {
+		t.Errorf("latency 0 -> 1.5s = %s, want REGRESSION", got)
+	}
+	if got := compareStatus(percentChange(0, 40), 0, 10); got != "improved" {
+		t.Errorf("tok/s 0 -> 40 = %s, want improved", got)
+	}
+	if got := compareStatus(percentChange(50, 0), 0, 10); got != "REGRESSION" {
+		t.Errorf("tok/s 50 -> 0 = %s, want REGRESSION", got)
+	}
+	if got := compareStatus(percentChange(50, 52), percentChange(1, 1.05), 10); got != "ok" {
+		t.Errorf("small drift = %s, want ok", got)
+	}
+}
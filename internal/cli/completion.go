@@ -0,0 +1,144 @@
+/*
+PURPOSE:
+  Defines the 'completion' subcommand and dynamic flag completions.
+  Tab-completes model names for `run --models` from the live hosts.
+
+REQUIREMENTS:
+  User-specified:
+  - completion [bash|zsh|fish|powershell] via cobra's generators.
+  - --models completes from /api/tags, respecting --urls already on the line.
+
+  Implementation-discovered:
+  - Replaces cobra's default completion command so the shells are explicit
+    in --help and the generator choice (bash V2 with descriptions) is ours.
+  - During completion cobra parses the flags already typed, so urlsOverride
+    and cfgFile are populated; the config file supplies URLs otherwise.
+  - --models is a comma-separated list: only the last element is completed,
+    and already-chosen models are not offered again.
+  - Completion must stay fast and silent: a short client timeout, errors go
+    to cobra's debug log (BASH_COMP_DEBUG_FILE), never stdout.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/engine.GetModels()
+  - Uses: internal/config
+
+ERROR HANDLING:
+  - Unreachable hosts are skipped; completion falls back to nothing.
+
+IMPLEMENTATION RULES:
+  - No logging through output.Logger (it writes to stdout, which the shell reads).
+
+USAGE:
+  source <(forest-runner completion bash)
+  forest-runner run --urls http://gpu1:11434 --models <TAB>
+
+SELF-HEALING INSTRUCTIONS:
+  - No suggestions: set BASH_COMP_DEBUG_FILE=/tmp/comp.log and retry to see why.
+
+RELATED FILES:
+  - internal/cli/run.go
+
+MAINTENANCE:
+  - Register completions next to the flag definition (run.go init); init
+    order across files means the flag must already exist.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// completionTimeout bounds each /api/tags request made while the user waits on <TAB>.
+const completionTimeout = 2 * time.Second
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for forest-runner.
+
+  bash:       source <(forest-runner completion bash)
+  zsh:        forest-runner completion zsh > "${fpath[1]}/_forest-runner"
+  fish:       forest-runner completion fish > ~/.config/fish/completions/forest-runner.fish
+  powershell: forest-runner completion powershell | Out-String | Invoke-Expression
+
+With completions loaded, run --models <TAB> lists models from the hosts in
+--urls (or the config file).`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(out, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(out)
+		case "fish":
+			return rootCmd.GenFishCompletion(out, true)
+		case "powershell":
+			return rootCmd.GenPowerShellCompletionWithDesc(out)
+		}
+		return fmt.Errorf("unsupported shell %q", args[0])
+	},
+}
+
+// completeModels suggests model names from the target hosts for a comma-separated --models value.
+func completeModels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("loading config: %v", err), false)
+		cfg = config.DefaultConfig()
+	}
+	if len(urlsOverride) > 0 {
+		cfg.URLs = urlsOverride
+	}
+	if err := cfg.NormalizeURLs(); err != nil {
+		cobra.CompDebugln(fmt.Sprintf("normalizing urls: %v", err), false)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Complete only the element after the last comma
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	chosen := make(map[string]bool)
+	for _, m := range strings.Split(prefix, ",") {
+		chosen[m] = true
+	}
+
+	e := engine.New(cfg)
+	e.Client.Timeout = completionTimeout
+
+	seen := make(map[string]bool)
+	var suggestions []string
+	for _, url := range cfg.URLs {
+		models, err := e.GetModels(cmd.Context(), url)
+		if err != nil {
+			cobra.CompDebugln(fmt.Sprintf("listing models on %s: %v", url, err), false)
+			continue
+		}
+		for _, m := range models {
+			if seen[m] || chosen[m] {
+				continue
+			}
+			seen[m] = true
+			suggestions = append(suggestions, prefix+m)
+		}
+	}
+	sort.Strings(suggestions)
+	return suggestions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func init() {
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+}
@@ -1,16 +1,25 @@
 package cli
 
 import (
+	"bytes"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/daryltucker/forest-runner/internal/assets"
 	"github.com/daryltucker/forest-runner/internal/output"
 	"github.com/spf13/cobra"
 )
 
+var (
+	installForce   bool
+	installDryRun  bool
+	uninstallAll   bool
+	uninstallForce bool
+)
+
 var functionsCmd = &cobra.Command{
 	Use:   "functions",
 	Short: "Manage JQ functions for result analysis",
@@ -20,54 +29,238 @@ var installCmd = &cobra.Command{
 	Use:   "install",
 	Short: "Install specialized JQ functions to ~/.config/vecq/functions/",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		home, err := os.UserHomeDir()
+		targetDir, err := functionsDir()
 		if err != nil {
-			return fmt.Errorf("failed to get user home directory: %w", err)
+			return err
 		}
+		output.Logger.Info("Installing JQ functions...", "target", targetDir, "dry_run", installDryRun)
 
-		targetDir := filepath.Join(home, ".config", "vecq", "functions")
-		output.Logger.Info("Installing JQ functions...", "target", targetDir)
-
-		if err := os.MkdirAll(targetDir, 0755); err != nil {
-			return fmt.Errorf("failed to create target directory %s: %w", targetDir, err)
+		if !installDryRun {
+			if err := os.MkdirAll(targetDir, 0755); err != nil {
+				return fmt.Errorf("failed to create target directory %s: %w", targetDir, err)
+			}
 		}
 
-		// Read embedded files from internal/assets/functions/
-		entries, err := fs.ReadDir(assets.Functions, "functions")
+		names, err := embeddedFunctions()
 		if err != nil {
-			return fmt.Errorf("failed to read embedded functions: %w", err)
+			return err
 		}
 
-		count := 0
-		for _, entry := range entries {
-			if entry.IsDir() {
+		installed, skipped, overwritten := 0, 0, 0
+		for _, name := range names {
+			// Existing files may be hand-edited; only --force replaces them
+			status, err := functionStatus(targetDir, name)
+			if err != nil {
+				output.Logger.Error("Failed to check installed function", "name", name, "error", err)
+				continue
+			}
+			switch {
+			case status == statusInstalled:
+				output.Logger.Info("Function already up to date", "name", name)
+				skipped++
 				continue
+			case status == statusModified && !installForce:
+				output.Logger.Warn("Skipping existing function that differs from the bundled copy (use --force to overwrite)", "name", name)
+				skipped++
+				continue
+			}
+
+			targetPath := filepath.Join(targetDir, name)
+			if installDryRun {
+				output.Logger.Info("Would write function", "path", targetPath, "overwrite", status == statusModified)
+			} else {
+				// Read file content
+				content, err := fs.ReadFile(assets.Functions, "functions/"+name)
+				if err != nil {
+					output.Logger.Error("Failed to read embedded file", "file", name, "error", err)
+					continue
+				}
+
+				// Write to target
+				if err := os.WriteFile(targetPath, content, 0644); err != nil {
+					output.Logger.Error("Failed to write to target", "path", targetPath, "error", err)
+					continue
+				}
+			}
+
+			if status == statusModified {
+				overwritten++
+			} else {
+				installed++
+			}
+			if !installDryRun {
+				if status == statusModified {
+					output.Logger.Info("Overwrote function", "name", name)
+				} else {
+					output.Logger.Info("Installed function", "name", name)
+				}
 			}
+		}
+
+		output.Logger.Info("Installation Complete", "installed", installed, "skipped", skipped, "overwritten", overwritten, "dry_run", installDryRun)
+		return nil
+	},
+}
+
+var listFunctionsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bundled JQ functions and whether they are installed",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := functionsDir()
+		if err != nil {
+			return err
+		}
+		names, err := embeddedFunctions()
+		if err != nil {
+			return err
+		}
 
-			// Read file content
-			content, err := fs.ReadFile(assets.Functions, "functions/"+entry.Name())
+		fmt.Printf("Functions directory: %s\n", targetDir)
+		for _, name := range names {
+			status, err := functionStatus(targetDir, name)
 			if err != nil {
-				output.Logger.Error("Failed to read embedded file", "file", entry.Name(), "error", err)
-				continue
+				return err
+			}
+			fmt.Printf("  %-24s %s\n", name, status)
+		}
+		return nil
+	},
+}
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall [name...]",
+	Short: "Remove installed JQ functions (only files bundled with forest-runner)",
+	Example: `  forest-runner functions uninstall forest_runner
+  forest-runner functions uninstall --all`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if uninstallAll == (len(args) > 0) {
+			return fmt.Errorf("name a function to remove, or pass --all")
+		}
+
+		targetDir, err := functionsDir()
+		if err != nil {
+			return err
+		}
+		names, err := embeddedFunctions()
+		if err != nil {
+			return err
+		}
+
+		// Only names from the embedded set are ever removed, so user-added files are safe
+		targets := names
+		if !uninstallAll {
+			targets = nil
+			for _, arg := range args {
+				name := arg
+				if !strings.HasSuffix(name, ".jq") {
+					name += ".jq"
+				}
+				if !contains(names, name) {
+					return fmt.Errorf("%s is not a bundled function (see 'forest-runner functions list')", arg)
+				}
+				targets = append(targets, name)
 			}
+		}
 
-			// Write to target
-			targetPath := filepath.Join(targetDir, entry.Name())
-			if err := os.WriteFile(targetPath, content, 0644); err != nil {
-				output.Logger.Error("Failed to write to target", "path", targetPath, "error", err)
+		removed := 0
+		for _, name := range targets {
+			status, err := functionStatus(targetDir, name)
+			if err != nil {
+				return err
+			}
+			switch status {
+			case statusNotInstalled:
+				if !uninstallAll {
+					output.Logger.Info("Function not installed", "name", name)
+				}
 				continue
+			case statusModified:
+				if !uninstallForce {
+					output.Logger.Warn("Skipping locally modified function (use --force to remove)", "name", name)
+					continue
+				}
 			}
 
-			output.Logger.Info("Installed function", "name", entry.Name())
-			count++
+			path := filepath.Join(targetDir, name)
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", path, err)
+			}
+			output.Logger.Info("Removed function", "name", name)
+			removed++
 		}
 
-		output.Logger.Info("Installation Complete", "total_files", count)
+		output.Logger.Info("Uninstall Complete", "removed", removed)
 		return nil
 	},
 }
 
+// Installation states reported by functionStatus.
+const (
+	statusInstalled    = "installed"
+	statusModified     = "installed (modified)"
+	statusNotInstalled = "not installed"
+)
+
+// functionsDir is where vecq looks for JQ functions.
+func functionsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "vecq", "functions"), nil
+}
+
+// embeddedFunctions lists the JQ files bundled in internal/assets/functions/.
+func embeddedFunctions() ([]string, error) {
+	entries, err := fs.ReadDir(assets.Functions, "functions")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded functions: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// functionStatus compares an installed function with the bundled copy.
+func functionStatus(targetDir, name string) (string, error) {
+	installed, err := os.ReadFile(filepath.Join(targetDir, name))
+	if os.IsNotExist(err) {
+		return statusNotInstalled, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	bundled, err := fs.ReadFile(assets.Functions, "functions/"+name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read embedded file %s: %w", name, err)
+	}
+	if !bytes.Equal(installed, bundled) {
+		return statusModified, nil
+	}
+	return statusInstalled, nil
+}
+
+// contains reports whether s is in list.
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	functionsCmd.AddCommand(installCmd)
+	installCmd.Flags().BoolVar(&installForce, "force", false, "Overwrite installed functions that differ from the bundled copy")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "List what would be written without touching disk")
+	functionsCmd.AddCommand(listFunctionsCmd)
+	functionsCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().BoolVar(&uninstallAll, "all", false, "Remove every bundled function")
+	uninstallCmd.Flags().BoolVar(&uninstallForce, "force", false, "Also remove functions that were edited after install")
 	rootCmd.AddCommand(functionsCmd)
 }
@@ -0,0 +1,78 @@
+/*
+PURPOSE:
+  Defines the 'init' subcommand.
+  Scaffolds a fully-commented configuration file for new users.
+
+REQUIREMENTS:
+  User-specified:
+  - Write forest_runner.yaml (or -o path) containing every config key.
+  - Refuse to overwrite an existing file unless --force is passed.
+
+  Implementation-discovered:
+  - Content is rendered by config.Scaffold() so defaults stay in sync.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/config.Scaffold()
+
+ERROR HANDLING:
+  - Returns error if the target exists (without --force) or cannot be written.
+
+IMPLEMENTATION RULES:
+  - Never clobber user files silently.
+
+USAGE:
+  forest-runner init [-o path] [--force]
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/config/scaffold.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initOutput string
+	initForce  bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a fully-commented default config file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(initOutput); err == nil && !initForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", initOutput)
+		}
+
+		data, err := config.Scaffold()
+		if err != nil {
+			return fmt.Errorf("failed to render config: %w", err)
+		}
+
+		if err := os.WriteFile(initOutput, data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", initOutput, err)
+		}
+
+		output.Logger.Info("Config written", "path", initOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().StringVarP(&initOutput, "output", "o", "forest_runner.yaml", "Path of the config file to write")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "Overwrite the file if it already exists")
+}
@@ -0,0 +1,138 @@
+/*
+PURPOSE:
+  Defines the 'inventory' subcommand.
+  Snapshots which models are deployed on which backends, with no inference.
+
+REQUIREMENTS:
+  User-specified:
+  - Discovery across all URLs, written as JSON or CSV (url, model, size,
+    quantization, family).
+  - Honor include/exclude filters so it lists what `run` would test.
+
+  Implementation-discovered:
+  - Format follows the --out extension (.csv, anything else JSON), so the
+    common case needs one flag; stdout gets JSON unless --format says csv.
+  - Progress and warnings go to the logger, so stdout stays parseable.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/engine.Inventory()
+  - Uses: internal/cli/overrides.go (loadConfig), internal/output (writers)
+
+ERROR HANDLING:
+  - Returns error for bad flags or config, an unwritable --out, or when no
+    backend could be listed.
+
+IMPLEMENTATION RULES:
+  - Read-only against the backends.
+
+USAGE:
+  forest-runner inventory --out fleet.json
+  forest-runner inventory --exclude embed --format csv > fleet.csv
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/engine/inventory.go
+  - internal/output/inventory.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	inventoryOut    string
+	inventoryFormat string
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Write the model inventory of every backend (no inference)",
+	Long: `Discover the models on every configured backend and write url, model, size,
+family, parameter size, quantization and context length as JSON or CSV.
+include/exclude filters apply, so the inventory lists what 'run' would test.`,
+	Example: `  # JSON snapshot of the fleet
+  forest-runner inventory --out fleet.json
+
+  # CSV for a spreadsheet, skipping embedding models
+  forest-runner inventory --exclude embed --out fleet.csv
+
+  # Feed the model names into a later run
+  forest-runner run --models "$(forest-runner inventory | jq -r '[.[].model] | unique | join(",")')"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(inventoryFormat)
+		if format == "" {
+			format = "json"
+			if strings.EqualFold(filepath.Ext(inventoryOut), ".csv") {
+				format = "csv"
+			}
+		}
+		if format != "json" && format != "csv" {
+			return fmt.Errorf("invalid --format %q (expected json or csv)", inventoryFormat)
+		}
+
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := cfg.NormalizeURLs(); err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		entries, err := engine.New(cfg).Inventory(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if inventoryOut != "" {
+			f, err := os.Create(inventoryOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", inventoryOut, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "csv" {
+			err = output.WriteInventoryCSV(w, entries)
+		} else {
+			err = output.WriteInventoryJSON(w, entries)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write inventory: %w", err)
+		}
+		if inventoryOut != "" {
+			output.Logger.Info("Wrote inventory", "path", inventoryOut, "models", len(entries))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.Flags().StringVar(&inventoryOut, "out", "", "File to write (default: stdout)")
+	inventoryCmd.Flags().StringVar(&inventoryFormat, "format", "", "json or csv (default: from the --out extension, else json)")
+	inventoryCmd.Flags().StringSliceVar(&urlsOverride, "urls", nil, "Comma-separated list of URLs")
+	inventoryCmd.Flags().StringVar(&caCertOverride, "cacert", "", "PEM file of a private CA to trust for https backends")
+	inventoryCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (self-signed dev boxes only)")
+	inventoryCmd.Flags().StringVar(&proxyOverride, "proxy", "", "Proxy for backend requests: http://, https:// or socks5://host:port")
+	inventoryCmd.Flags().StringSliceVar(&includeOverride, "include", nil, "Comma-separated substrings; only models matching any of them are listed")
+	inventoryCmd.Flags().StringSliceVar(&excludeOverride, "exclude", nil, "Comma-separated list of substrings to exclude from model names")
+	inventoryCmd.Flags().StringArrayVar(&includeRegex, "include-regex", nil, "Only list models matching this Go regexp (repeatable)")
+	inventoryCmd.Flags().StringArrayVar(&excludeRegex, "exclude-regex", nil, "Hide models matching this Go regexp (repeatable)")
+}
@@ -6,27 +6,37 @@ PURPOSE:
 REQUIREMENTS:
   User-specified:
   - List available models.
+  - Show size and modified date, largest first.
+  - Honour --config and include/exclude filters, the same way run does.
 
   Implementation-discovered:
   - Useful validation step before full run.
+  - Sizes use decimal units (GB = 10^9) to match `ollama list`.
+  - OpenAI-compatible backends report names only; size/date show "-".
+  - Filtered-out models are counted, not hidden silently, so an overly broad
+    exclude is obvious.
 
 ARCHITECTURE INTEGRATION:
-  - Calls: internal/engine.GetModels() (via Client)
+  - Calls: internal/engine.ListModels() (via Client)
+  - Uses: internal/cli/overrides.go (loadConfig)
 
 ERROR HANDLING:
   - Prints error if URL incorrect.
+  - Returns error if the config can't be loaded or a filter regexp is invalid.
 
 IMPLEMENTATION RULES:
   - Simple output to stdout.
 
 USAGE:
   forest-runner list-models --urls ...
+  forest-runner list-models --config fleet.yaml --exclude embed
 
 SELF-HEALING INSTRUCTIONS:
   - None.
 
 RELATED FILES:
   - internal/engine/client.go
+  - internal/cli/overrides.go
 
 MAINTENANCE:
   - None.
@@ -37,8 +47,10 @@ package cli
 import (
 	"fmt"
 	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
 
-	"github.com/daryltucker/forest-runner/internal/config"
 	"github.com/daryltucker/forest-runner/internal/engine"
 	"github.com/spf13/cobra"
 )
@@ -47,27 +59,33 @@ var listModelsCmd = &cobra.Command{
 	Use:   "list-models",
 	Short: "List available models on target hosts",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Reuse config loading/override logic?
-		// Or just use flags?
-		// Let's try to be consistent.
-		cfg := config.DefaultConfig()
-		// (Skipping file load for simplicity unless needed, or reuse loaded cfg if we abstracted it)
-
-		if len(urlsOverride) > 0 {
-			cfg.URLs = urlsOverride
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := cfg.NormalizeURLs(); err != nil {
+			return err
 		}
 
 		e := engine.New(cfg)
 
 		for _, url := range cfg.URLs {
 			fmt.Printf("Querying %s...\n", url)
-			models, err := e.GetModels(url)
+			models, skipped, err := e.ListModels(cmd.Context(), url)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				continue
 			}
+			// Largest first: the expensive models are the ones worth deciding about
+			sort.SliceStable(models, func(i, j int) bool { return models[i].Size > models[j].Size })
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "  NAME\tSIZE\tMODIFIED")
 			for _, m := range models {
-				fmt.Printf("- %s\n", m)
+				fmt.Fprintf(tw, "  %s\t%s\t%s\n", m.Name, formatSize(m.Size), formatModified(m.ModifiedAt))
+			}
+			tw.Flush()
+			if len(skipped) > 0 {
+				fmt.Printf("  (%d filtered out by include/exclude)\n", len(skipped))
 			}
 		}
 
@@ -75,11 +93,40 @@ var listModelsCmd = &cobra.Command{
 	},
 }
 
+// formatSize renders a byte count like Ollama does (e.g. "4.7 GB"); 0 means unknown.
+func formatSize(b int64) string {
+	if b <= 0 {
+		return "-"
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	v := float64(b)
+	i := 0
+	for v >= 1000 && i < len(units)-1 {
+		v /= 1000
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", b)
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
+
+// formatModified renders a model's modification time as a local date; zero means unknown.
+func formatModified(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
 func init() {
 	rootCmd.AddCommand(listModelsCmd)
-	// Reuse overrides flags from run? Or define new ones?
-	// If I defined them on runCmd, they belong to runCmd.
-	// I should define them here too, or moving them to Persistent on root if shared.
-	// `urls` seems common.
 	listModelsCmd.Flags().StringSliceVar(&urlsOverride, "urls", nil, "Comma-separated list of URLs")
+	listModelsCmd.Flags().StringVar(&caCertOverride, "cacert", "", "PEM file of a private CA to trust for https backends")
+	listModelsCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (self-signed dev boxes only)")
+	listModelsCmd.Flags().StringVar(&proxyOverride, "proxy", "", "Proxy for backend requests: http://, https:// or socks5://host:port")
+	listModelsCmd.Flags().StringSliceVar(&includeOverride, "include", nil, "Comma-separated substrings; only models matching any of them are listed")
+	listModelsCmd.Flags().StringSliceVar(&excludeOverride, "exclude", nil, "Comma-separated list of substrings to exclude from model names")
+	listModelsCmd.Flags().StringArrayVar(&includeRegex, "include-regex", nil, "Only list models matching this Go regexp (repeatable)")
+	listModelsCmd.Flags().StringArrayVar(&excludeRegex, "exclude-regex", nil, "Hide models matching this Go regexp (repeatable)")
 }
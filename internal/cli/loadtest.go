@@ -0,0 +1,138 @@
+/*
+PURPOSE:
+  Defines the 'loadtest' subcommand.
+  Measures serving capacity: aggregate tokens/sec one model sustains with N
+  simultaneous requests, alongside latency percentiles and error rate.
+
+REQUIREMENTS:
+  User-specified:
+  - Concurrent /api/generate requests against one model for a fixed duration.
+  - Report aggregate tokens/sec, p95 latency and error rate.
+
+  Implementation-discovered:
+  - Loads the config so prompt, inference_configs, endpoint, protocol, headers
+    and TLS apply exactly as in `run`; --url picks one backend (default: the
+    first configured URL).
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/engine.LoadTest()
+  - Uses: internal/cli/overrides.go (loadConfig)
+
+ERROR HANDLING:
+  - Returns error for bad flags or config, and when every request failed.
+
+IMPLEMENTATION RULES:
+  - The report goes to stdout after the test; logs are not interleaved with it.
+
+USAGE:
+  forest-runner loadtest --model llama3:8b --url http://gpu1:11434 -n 8 --duration 2m
+
+SELF-HEALING INSTRUCTIONS:
+  - Many "timeout" errors: requests are queueing on the server; lower -n or
+    raise OLLAMA_NUM_PARALLEL.
+
+RELATED FILES:
+  - internal/engine/loadtest.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	loadTestModel       string
+	loadTestURL         string
+	loadTestConcurrency int
+	loadTestDuration    time.Duration
+)
+
+var loadTestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Measure a model's throughput under concurrent requests",
+	Long: `Keep N requests in flight against one model for a fixed duration and report
+the aggregate tokens/sec it sustains, per-request speed, latency percentiles
+and error rate. The prompt and the first inference config come from the config file.`,
+	Example: `  # 8 concurrent requests for 2 minutes
+  forest-runner loadtest --model llama3:8b --url http://gpu1:11434 -n 8 --duration 2m
+
+  # Compare against single-request speed
+  forest-runner loadtest --model llama3:8b -n 1 --duration 1m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		url := loadTestURL
+		if url == "" {
+			if len(cfg.URLs) == 0 {
+				return fmt.Errorf("no URL: pass --url or set urls in the config")
+			}
+			url = cfg.URLs[0]
+		}
+		if url, err = config.NormalizeURL(url); err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		report, err := engine.LoadTest(cmd.Context(), cfg, url, loadTestModel, engine.LoadTestOptions{
+			Concurrency: loadTestConcurrency,
+			Duration:    loadTestDuration,
+		})
+		if report.Requests > 0 {
+			printLoadTestReport(report)
+		}
+		if err != nil {
+			return err
+		}
+		if report.Requests > 0 && report.Errors == report.Requests {
+			return fmt.Errorf("all %d requests failed", report.Requests)
+		}
+		return nil
+	},
+}
+
+// printLoadTestReport writes the load test summary to stdout.
+func printLoadTestReport(r engine.LoadTestReport) {
+	fmt.Printf("\nLoad test: %s @ %s\n", r.Model, r.URL)
+	fmt.Printf("  concurrency:        %d\n", r.Concurrency)
+	fmt.Printf("  wall time:          %s\n", r.Wall.Round(time.Millisecond))
+	fmt.Printf("  requests:           %d (%.1f/s)\n", r.Requests, float64(r.Requests)/r.Wall.Seconds())
+	fmt.Printf("  errors:             %d (%.1f%%)", r.Errors, r.ErrorRate()*100)
+	if len(r.ErrorKinds) > 0 {
+		kinds := make([]string, 0, len(r.ErrorKinds))
+		for kind, n := range r.ErrorKinds {
+			kinds = append(kinds, fmt.Sprintf("%s=%d", kind, n))
+		}
+		sort.Strings(kinds)
+		fmt.Printf("  [%s]", strings.Join(kinds, " "))
+	}
+	fmt.Println()
+	fmt.Printf("  aggregate tok/s:    %.1f (%d tokens)\n", r.TokensPerSecond, r.Tokens)
+	fmt.Printf("  per-request tok/s:  mean %.1f, median %.1f, p95 %.1f\n", r.PerRequestTPS.Mean, r.PerRequestTPS.Median, r.PerRequestTPS.P95)
+	fmt.Printf("  latency (s):        p50 %.3f, p90 %.3f, p95 %.3f, p99 %.3f\n", r.Latency.P50, r.Latency.P90, r.Latency.P95, r.Latency.P99)
+}
+
+func init() {
+	rootCmd.AddCommand(loadTestCmd)
+	loadTestCmd.Flags().StringVar(&loadTestModel, "model", "", "Model to load test")
+	loadTestCmd.Flags().StringVar(&loadTestURL, "url", "", "Backend URL (default: the first configured URL)")
+	loadTestCmd.Flags().IntVarP(&loadTestConcurrency, "concurrency", "n", 4, "Simultaneous in-flight requests")
+	loadTestCmd.Flags().DurationVar(&loadTestDuration, "duration", 30*time.Second, "How long to keep starting requests")
+	loadTestCmd.Flags().StringVar(&caCertOverride, "cacert", "", "PEM file of a private CA to trust for https backends")
+	loadTestCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (self-signed dev boxes only)")
+	loadTestCmd.Flags().StringVar(&proxyOverride, "proxy", "", "Proxy for backend requests: http://, https:// or socks5://host:port")
+	loadTestCmd.MarkFlagRequired("model")
+	loadTestCmd.RegisterFlagCompletionFunc("model", completeModels)
+}
@@ -0,0 +1,291 @@
+/*
+PURPOSE:
+  Applies command-line flag overrides on top of the loaded config.
+  Shared by every subcommand that talks to backends (run, list-models, ping),
+  so --config and the override flags behave the same everywhere.
+
+REQUIREMENTS:
+  User-specified:
+  - One helper for the override logic instead of per-command copies.
+  - list-models honours --config and include/exclude like run does.
+
+  Implementation-discovered:
+  - The flag variables are package globals shared by the commands. Only the
+    invoked command's flags are parsed, so flags a command doesn't register
+    stay at their zero value and Changed() is false; applyOverrides is safe
+    to call from any command.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/run.go, list_models.go, ping.go
+  - Uses: internal/config
+
+ERROR HANDLING:
+  - Returns error if the config can't be loaded, a prompt file can't be read,
+    a --header/--var value or --format schema is malformed, the TLS files are unusable or the
+    proxy URL is invalid.
+  - Warns loudly when certificate verification is disabled.
+
+IMPLEMENTATION RULES:
+  - Flags override the config file; the config file overrides defaults.
+  - Booleans and numbers use Changed() so an explicit false/0 still applies.
+
+USAGE:
+  cfg, err := loadConfig(cmd)
+
+SELF-HEALING INSTRUCTIONS:
+  - A flag seems ignored on one command: it must be registered on that command.
+
+RELATED FILES:
+  - internal/cli/run.go
+  - internal/config/config.go
+
+MAINTENANCE:
+  - Add new override flags here, and register them on each command that uses them.
+*/
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// loadConfig loads the --config file (or defaults) and applies cmd's flag overrides.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyOverrides(cmd, cfg); err != nil {
+		return nil, err
+	}
+	if _, err := cfg.TLSConfig(); err != nil {
+		return nil, err
+	}
+	if _, err := cfg.ProxyURL(); err != nil {
+		return nil, err
+	}
+	if cfg.InsecureSkipVerify {
+		output.Logger.Warn("TLS CERTIFICATE VERIFICATION IS DISABLED (insecure_skip_verify): https backends are not authenticated")
+	}
+	return cfg, nil
+}
+
+// parseFormatFlag turns --format into a config format: "json" as is, anything
+// else must be a JSON schema object.
+func parseFormatFlag(value string) (interface{}, error) {
+	if value == "json" {
+		return value, nil
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &schema); err != nil {
+		return nil, fmt.Errorf("invalid --format %q: expected \"json\" or a JSON schema object: %w", value, err)
+	}
+	return schema, nil
+}
+
+// applyOverrides copies every flag the user set on cmd into cfg.
+func applyOverrides(cmd *cobra.Command, cfg *config.Config) error {
+	if len(urlsOverride) > 0 {
+		cfg.URLs = urlsOverride
+	}
+	if caCertOverride != "" {
+		cfg.CACertFile = caCertOverride
+	}
+	if proxyOverride != "" {
+		cfg.Proxy = proxyOverride
+	}
+	if cmd.Flags().Changed("insecure") {
+		cfg.InsecureSkipVerify = insecure
+	}
+	if outputOverride != "" {
+		cfg.OutputDir = outputOverride
+	}
+	if promptFile != "" {
+		data, err := readPromptFile(promptFile)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt file: %w", err)
+		}
+		cfg.Prompt = string(data)
+	}
+	if promptInline != "" {
+		cfg.Prompt = promptInline
+	}
+	if promptDir != "" {
+		cfg.PromptDir = promptDir
+	}
+	if cmd.Flags().Changed("prompt-templates") {
+		cfg.PromptTemplates = promptTemplates
+	}
+	if len(imagesOverride) > 0 {
+		cfg.Images = imagesOverride
+	}
+	if cmd.Flags().Changed("image-sweep") {
+		cfg.ImageSweep = imageSweep
+	}
+	if formatOverride != "" {
+		format, err := parseFormatFlag(formatOverride)
+		if err != nil {
+			return err
+		}
+		cfg.Format = format
+	}
+	if len(includeOverride) > 0 {
+		cfg.Include = includeOverride
+	}
+	if len(includeRegex) > 0 {
+		cfg.IncludeRegex = includeRegex
+	}
+	if len(excludeRegex) > 0 {
+		cfg.ExcludeRegex = excludeRegex
+	}
+	if len(excludeOverride) > 0 {
+		cfg.Exclude = excludeOverride
+	}
+	if len(modelsOverride) > 0 {
+		cfg.Models = modelsOverride
+	}
+	if len(outputsOverride) > 0 {
+		cfg.Outputs = outputsOverride
+	}
+	if cmd.Flags().Changed("concurrency") {
+		cfg.Concurrency = concurrencyOverride
+	}
+	if endpointOverride != "" {
+		cfg.Endpoint = endpointOverride
+	}
+	if cmd.Flags().Changed("interactive") {
+		cfg.Interactive = interactive
+	}
+	if cmd.Flags().Changed("token-timings") {
+		cfg.TokenTimings = tokenTimings
+	}
+	if cmd.Flags().Changed("load-timeout") {
+		cfg.LoadTimeout = loadTimeout
+	}
+	if cmd.Flags().Changed("timeout-overall") {
+		cfg.OverallTimeout = overallTimeout
+	}
+	if cmd.Flags().Changed("discovery-timeout") {
+		cfg.DiscoveryTimeout = discoveryTimeout
+	}
+	if cmd.Flags().Changed("inter-run-delay") {
+		cfg.InterRunDelay = interRunDelay
+	}
+	if cmd.Flags().Changed("gpu-only") {
+		cfg.GPUOnly = gpuOnly
+	}
+	if cmd.Flags().Changed("cost-per-gpu-hour") {
+		cfg.CostPerGPUHour = costPerGPUHour
+	}
+	if cmd.Flags().Changed("gpu-count") {
+		cfg.GPUCount = gpuCount
+	}
+	if cmd.Flags().Changed("max-vram-bytes") {
+		cfg.MaxVRAMBytes = maxVRAMBytes
+	}
+	if cmd.Flags().Changed("cpu-only-allowed") {
+		cfg.CPUOnlyAllowed = cpuOnlyAllowed
+	}
+	if keepAliveOverride != "" {
+		cfg.KeepAlive = keepAliveOverride
+	}
+	if cmd.Flags().Changed("repeat") {
+		cfg.Repeat = repeatOverride
+	}
+	if cmd.Flags().Changed("model-concurrency") {
+		cfg.ModelConcurrency = modelConcurrency
+	}
+	if failFast != "" {
+		cfg.FailFast = failFast
+	}
+	if cmd.Flags().Changed("fail-fast-run") {
+		cfg.FailFastRun = failFastRun
+	}
+	if modelOrder != "" {
+		cfg.ModelOrder = modelOrder
+	}
+	if cmd.Flags().Changed("shuffle") {
+		cfg.Shuffle = shuffle
+	}
+	if cmd.Flags().Changed("seed") {
+		cfg.Seed = seed
+	}
+	if cmd.Flags().Changed("max-models") {
+		cfg.MaxModels = maxModels
+	}
+	if cmd.Flags().Changed("rate-limit") {
+		cfg.GlobalRateLimit = globalRateLimit
+	}
+	if resumePath != "" {
+		cfg.Resume = resumePath
+	}
+	if cmd.Flags().Changed("json-indent") {
+		cfg.JSONIndent = jsonIndent
+	}
+	if cmd.Flags().Changed("csv-append") {
+		cfg.CSVAppend = csvAppend
+	}
+	if cmd.Flags().Changed("run-dir") {
+		cfg.RunDirLayout = runDirLayout
+	}
+	if cmd.Flags().Changed("include-response") {
+		cfg.IncludeResponse = includeResponse
+	}
+	if cmd.Flags().Changed("response-max-chars") {
+		cfg.ResponseMaxChars = responseMaxChars
+	}
+	if csvDelimiter != "" {
+		cfg.CSVDelimiter = csvDelimiter
+	}
+	if cmd.Flags().Changed("csv-precision") {
+		cfg.CSVPrecision = csvPrecision
+	}
+	if metricsAddr != "" {
+		cfg.MetricsAddr = metricsAddr
+	}
+	if cmd.Flags().Changed("unload-after") {
+		cfg.UnloadAfter = unloadAfter
+	}
+	if cmd.Flags().Changed("pull") {
+		cfg.AutoPull = autoPull
+	}
+	if cmd.Flags().Changed("warmup") {
+		cfg.Warmup = warmup
+	}
+	if cmd.Flags().Changed("stream-benchmark") {
+		cfg.StreamBenchmark = streamBenchmark
+	}
+	if cmd.Flags().Changed("no-health-check") {
+		cfg.SkipHealthCheck = noHealthCheck
+	}
+	if cmd.Flags().Changed("health-check-only") {
+		cfg.HealthCheckOnly = healthCheckOnly
+	}
+	if len(ctxSweep) > 0 {
+		cfg.CtxSweep = ctxSweep
+	}
+	if cmd.Flags().Changed("max-tokens") {
+		cfg.MaxTokens = maxTokens
+	}
+	if cmd.Flags().Changed("verify-determinism") {
+		cfg.VerifyDeterminism = verifyDeterminism
+	}
+	if err := applyHeaderFlags(cfg, headerOverrides); err != nil {
+		return err
+	}
+	if err := applyVarFlags(cfg, varOverrides); err != nil {
+		return err
+	}
+	if err := applyLabelFlags(cfg, labelOverrides); err != nil {
+		return err
+	}
+	if runIDOverride != "" {
+		cfg.RunID = runIDOverride
+	}
+	return nil
+}
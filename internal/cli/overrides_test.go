@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestPrecedence checks file < env < flag for a value settable all three ways.
+func TestPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runner.yaml")
+	if err := os.WriteFile(path, []byte("concurrency: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldFile, oldConcurrency := cfgFile, concurrencyOverride
+	t.Cleanup(func() { cfgFile, concurrencyOverride = oldFile, oldConcurrency })
+	cfgFile = path
+
+	load := func(args ...string) int {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.Flags().IntVar(&concurrencyOverride, "concurrency", 0, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			t.Fatalf("loadConfig: %v", err)
+		}
+		return cfg.Concurrency
+	}
+
+	if got := load(); got != 2 {
+		t.Errorf("file only: concurrency = %d, want 2", got)
+	}
+	t.Setenv("FOREST_CONCURRENCY", "3")
+	if got := load(); got != 3 {
+		t.Errorf("file + env: concurrency = %d, want 3", got)
+	}
+	if got := load("--concurrency", "4"); got != 4 {
+		t.Errorf("file + env + flag: concurrency = %d, want 4", got)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+PURPOSE:
+  Defines the 'ping' subcommand.
+  Confirms every backend URL is reachable before a run.
+
+REQUIREMENTS:
+  User-specified:
+  - Report reachable/unreachable with latency for each URL.
+  - Exit non-zero if any host is down so it can gate a pipeline.
+  - Lighter than list-models: one small request per host.
+
+  Implementation-discovered:
+  - Loads the config so urls, url_protocols and headers (auth proxies) apply.
+  - Hosts are pinged in parallel so one dead host costs one timeout, not N;
+    output keeps the configured URL order.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/engine.Engine.Ping()
+  - Uses: internal/cli/overrides.go (loadConfig)
+
+ERROR HANDLING:
+  - Returns error if the config can't be loaded or any host is unreachable.
+
+IMPLEMENTATION RULES:
+  - No model is listed, loaded or benchmarked.
+
+USAGE:
+  forest-runner ping --urls http://gpu1:11434,http://gpu2:11434 --timeout 3s
+
+SELF-HEALING INSTRUCTIONS:
+  - All hosts DOWN with "connection refused": Ollama isn't listening on that
+    interface (set OLLAMA_HOST=0.0.0.0 on the server).
+
+RELATED FILES:
+  - internal/engine/ping.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+var pingTimeout time.Duration
+
+// pingResult is the outcome of pinging one URL.
+type pingResult struct {
+	version string
+	latency time.Duration
+	err     error
+}
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that every backend URL is reachable",
+	Example: `  # Gate a pipeline on the fleet being up
+  forest-runner ping --urls http://gpu1:11434,http://gpu2:11434 && forest-runner run
+
+  # Use the URLs from a config file, allowing slow hosts 10s
+  forest-runner ping --config fleet.yaml --timeout 10s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+		if err := cfg.NormalizeURLs(); err != nil {
+			return err
+		}
+		if pingTimeout <= 0 {
+			return fmt.Errorf("--timeout must be positive (got %s)", pingTimeout)
+		}
+
+		e := engine.New(cfg)
+		results := make([]pingResult, len(cfg.URLs))
+		var wg sync.WaitGroup
+		for i, url := range cfg.URLs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				version, latency, err := e.Ping(cmd.Context(), url, pingTimeout)
+				results[i] = pingResult{version: version, latency: latency, err: err}
+			}()
+		}
+		wg.Wait()
+
+		down := 0
+		for i, url := range cfg.URLs {
+			r := results[i]
+			if r.err != nil {
+				down++
+				fmt.Printf("DOWN  %s  %v\n", url, r.err)
+				continue
+			}
+			detail := cfg.ProtocolFor(url)
+			if r.version != "" {
+				detail += " " + r.version
+			}
+			fmt.Printf("UP    %s  %s  (%s)\n", url, r.latency.Round(time.Millisecond), detail)
+		}
+
+		if down > 0 {
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true // main prints the returned error
+			return fmt.Errorf("FAIL: %d of %d host(s) unreachable", down, len(cfg.URLs))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pingCmd)
+	pingCmd.Flags().StringSliceVar(&urlsOverride, "urls", nil, "Comma-separated list of URLs (overrides config)")
+	pingCmd.Flags().StringVar(&caCertOverride, "cacert", "", "PEM file of a private CA to trust for https backends")
+	pingCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (self-signed dev boxes only)")
+	pingCmd.Flags().StringVar(&proxyOverride, "proxy", "", "Proxy for backend requests: http://, https:// or socks5://host:port")
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", 5*time.Second, "Per-host timeout")
+}
@@ -0,0 +1,211 @@
+/*
+PURPOSE:
+  Defines the 'report' subcommand.
+  Prints a leaderboard summarizing a JSON Lines results file.
+
+REQUIREMENTS:
+  User-specified:
+  - Group by model, sort by mean tokens/sec.
+  - Columns: model, runs, mean tok/s, p95 latency, VRAM%.
+  - Output formats: table, csv, md.
+  - --fleet compares each model, prompt and config across URLs: min/mean/max tok/s and the
+    fastest URL.
+
+  Implementation-discovered:
+  - Failed rows are excluded from the statistics (they have no metrics).
+  - The fleet view is model.NewFleetAggregates, shared with the run summary.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/output.ReadResults(), internal/model stats helpers
+
+ERROR HANDLING:
+  - Returns error on unreadable file or unknown format.
+
+IMPLEMENTATION RULES:
+  - Read-only; never modifies the results file.
+
+USAGE:
+  forest-runner report ./results/model_results.json --format md
+  forest-runner report ./results/model_results.json --fleet
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/output/reader.go
+  - internal/model/stats.go
+
+MAINTENANCE:
+  - Add columns here when new per-result metrics are worth ranking.
+*/
+
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportFormat string
+	reportFleet  bool
+)
+
+// reportRow is one model's line in the leaderboard.
+type reportRow struct {
+	Model       string
+	Runs        int
+	MeanTPS     float64
+	P95Latency  float64 // seconds
+	MeanVRAMPct float64
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report <results.json>",
+	Short: "Summarize a results file as a leaderboard",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		results, err := output.ReadResults(args[0])
+		if err != nil {
+			return err
+		}
+
+		if reportFleet {
+			return renderFleet(results)
+		}
+
+		rows := buildReport(results)
+		header := []string{"Model", "Runs", "Mean Tk/s", "P95 Latency(s)", "VRAM%"}
+		records := make([][]string, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, []string{
+				r.Model,
+				fmt.Sprintf("%d", r.Runs),
+				fmt.Sprintf("%.1f", r.MeanTPS),
+				fmt.Sprintf("%.3f", r.P95Latency),
+				fmt.Sprintf("%.1f", r.MeanVRAMPct),
+			})
+		}
+
+		return renderTable(os.Stdout, reportFormat, header, records)
+	},
+}
+
+// renderFleet prints one line per (model, prompt, config) comparing its speed across URLs.
+func renderFleet(results []model.Result) error {
+	fleet := model.NewFleetAggregates(results)
+	header := []string{"Model", "Config", "URLs", "Runs", "Min Tk/s", "Mean Tk/s", "Max Tk/s", "Fastest URL"}
+	records := make([][]string, 0, len(fleet))
+	for _, f := range fleet {
+		name := f.Model
+		if f.PromptName != "" {
+			name += " [" + f.PromptName + "]"
+		}
+		cfgBytes, _ := json.Marshal(f.Config)
+		records = append(records, []string{
+			name,
+			string(cfgBytes),
+			fmt.Sprintf("%d", f.URLs),
+			fmt.Sprintf("%d", f.Runs),
+			fmt.Sprintf("%.1f", f.MinTPS),
+			fmt.Sprintf("%.1f", f.MeanTPS),
+			fmt.Sprintf("%.1f", f.MaxTPS),
+			f.FastestURL,
+		})
+	}
+	return renderTable(os.Stdout, reportFormat, header, records)
+}
+
+// buildReport groups successful results by model and ranks them by mean tokens/sec.
+func buildReport(results []model.Result) []reportRow {
+	byModel := make(map[string][]model.Result)
+	for _, r := range results {
+		if r.Error != "" {
+			continue
+		}
+		byModel[r.Model] = append(byModel[r.Model], r)
+	}
+
+	rows := make([]reportRow, 0, len(byModel))
+	for name, rs := range byModel {
+		tps := make([]float64, 0, len(rs))
+		latency := make([]float64, 0, len(rs))
+		vram := make([]float64, 0, len(rs))
+		for _, r := range rs {
+			tps = append(tps, r.TokensPerSecond)
+			latency = append(latency, r.Duration.Seconds())
+			vram = append(vram, r.VRAMPercentage)
+		}
+		rows = append(rows, reportRow{
+			Model:       name,
+			Runs:        len(rs),
+			MeanTPS:     model.ComputeStats(tps).Mean,
+			P95Latency:  model.Percentile(latency, 95),
+			MeanVRAMPct: model.ComputeStats(vram).Mean,
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].MeanTPS != rows[j].MeanTPS {
+			return rows[i].MeanTPS > rows[j].MeanTPS
+		}
+		return rows[i].Model < rows[j].Model
+	})
+	return rows
+}
+
+// renderTable writes a header and records as an aligned table, CSV, or Markdown.
+func renderTable(w io.Writer, format string, header []string, records [][]string) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		dashes := make([]string, len(header))
+		for i, h := range header {
+			dashes[i] = strings.Repeat("-", len(h))
+		}
+		fmt.Fprintln(tw, strings.Join(dashes, "\t"))
+		for _, rec := range records {
+			fmt.Fprintln(tw, strings.Join(rec, "\t"))
+		}
+		return tw.Flush()
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(records); err != nil {
+			return err
+		}
+		return cw.Error()
+	case "md":
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+		for _, rec := range records {
+			escaped := make([]string, len(rec))
+			for i, v := range rec {
+				escaped[i] = strings.ReplaceAll(v, "|", `\|`)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format %q (expected table, csv or md)", format)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.Flags().StringVar(&reportFormat, "format", "table", "Output format: table, csv or md")
+	reportCmd.Flags().BoolVar(&reportFleet, "fleet", false, "Compare each model and config across URLs (min/mean/max tok/s, fastest URL) instead of the leaderboard")
+}
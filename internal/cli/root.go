@@ -17,7 +17,7 @@ ARCHITECTURE INTEGRATION:
   - Modifies: Global configuration state (temporarily, until passed down).
 
 ERROR HANDLING:
-  - Returns error to main.go for exit code handling.
+  - Returns error to main.go for exit code handling (see ExitCode).
 
 IMPLEMENTATION RULES:
   - Use `PersistentFlags()` for flags available to all subcommands.
@@ -39,6 +39,14 @@ MAINTENANCE:
 package cli
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/daryltucker/forest-runner/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -46,18 +54,50 @@ var (
 	// cfgFile stores the path to the config file (if specified via flag)
 	cfgFile string
 
+	// logLevel and logFormat configure output.Logger before any subcommand runs
+	logLevel  string
+	logFormat string
+
 	rootCmd = &cobra.Command{
 		Use:   "forest-runner",
 		Short: "Benchmarking and testing tool for Ollama fleets",
 		Long:  `A systematic auditing tool for Ollama models. Use 'run --help' for benchmark options.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			logger, err := output.NewLogger(logLevel, logFormat)
+			if err != nil {
+				return err
+			}
+			output.SetLogger(logger)
+			return nil
+		},
 	}
 )
 
 // Execute executes the root command.
+// SIGINT/SIGTERM cancel the command context so runs can flush partial results;
+// a second signal terminates immediately.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop() // restore default handling for a second Ctrl-C
+	}()
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// ExitCode maps an error returned by Execute to a process exit code.
+// Interrupted runs exit with 130 (128 + SIGINT), following shell convention.
+func ExitCode(err error) int {
+	if errors.Is(err, engine.ErrInterrupted) {
+		return 130
+	}
+	return 1
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./forest_runner.yaml)")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level: debug, info, warn or error")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text or json")
 }
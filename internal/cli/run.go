@@ -21,7 +21,7 @@ ERROR HANDLING:
 
 IMPLEMENTATION RULES:
   - Setup flags in init().
-  - Logic: Load Config -> Override -> Engine.Run.
+  - Logic: Load Config -> Override -> Engine.Run (or Engine.Plan with --dry-run).
 
 USAGE:
   forest-runner run --urls https://...
@@ -31,6 +31,7 @@ SELF-HEALING INSTRUCTIONS:
 
 RELATED FILES:
   - internal/cli/root.go
+  - internal/cli/overrides.go
 
 MAINTENANCE:
   - Update when adding new CLI overrides.
@@ -40,20 +41,83 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/daryltucker/forest-runner/internal/config"
 	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/daryltucker/forest-runner/internal/output"
 	"github.com/spf13/cobra"
 )
 
 var (
 	urlsOverride        []string
+	caCertOverride      string
+	insecure            bool
+	proxyOverride       string
 	outputOverride      string
 	promptFile          string
+	promptInline        string
+	promptDir           string
+	imagesOverride      []string
+	promptTemplates     bool
+	imageSweep          bool
+	formatOverride      string
 	excludeOverride     []string
+	includeOverride     []string
+	includeRegex        []string
+	excludeRegex        []string
 	modelsOverride      []string
+	outputsOverride     []string
 	concurrencyOverride int
+	endpointOverride    string
+	interactive         bool
+	tokenTimings        bool
+	headerOverrides     []string
+	varOverrides        []string
+	labelOverrides      []string
+	runIDOverride       string
+	loadTimeout         time.Duration
+	interRunDelay       time.Duration
+	overallTimeout      time.Duration
+	discoveryTimeout    time.Duration
+	gpuOnly             bool
+	cpuOnlyAllowed      bool
+	maxVRAMBytes        int64
+	failFast            string
+	failFastRun         bool
+	costPerGPUHour      float64
+	gpuCount            int
+	keepAliveOverride   string
+	repeatOverride      int
+	warmup              bool
+	verifyDeterminism   bool
+	streamBenchmark     bool
+	noHealthCheck       bool
+	healthCheckOnly     bool
+	maxTokens           int
+	ctxSweep            []int
+	autoPull            bool
+	unloadAfter         bool
+	modelConcurrency    int
+	modelOrder          string
+	dryRun              bool
+	strict              bool
+	shuffle             bool
+	seed                int64
+	maxModels           int
+	globalRateLimit     int
+	resumePath          string
+	metricsAddr         string
+	csvAppend           bool
+	jsonIndent          bool
+	runDirLayout        bool
+	includeResponse     bool
+	responseMaxChars    int
+	csvDelimiter        string
+	csvPrecision        int
 )
 
 var runCmd = &cobra.Command{
@@ -65,11 +129,13 @@ The process follows a strict protocol:
 2. Health Check: Runs a streaming inference test to ensure the model is responsive.
 3. Benchmarking: Executes multiple inference configurations to collect performance metrics.
 
-Results are automatically saved to CSV and JSON formats, with automatic file versioning
-(e.g., results.json.1) to prevent overwriting previous data.
+Results are saved in the configured output formats (CSV and JSON by default). Each run
+gets the next free number, shared by all of its files, so previous results are never
+overwritten: model_results-3.csv pairs with model_results-3.json.
 
 Concurrency is handled at the BACKEND level. Each URL is processed by a dedicated worker.
-To maintain benchmark integrity, models within a single backend are tested sequentially.`,
+To maintain benchmark integrity, models within a single backend are tested sequentially
+unless --model-concurrency is raised (only safe on multi-GPU hosts).`,
 	Example: `  # Run with defaults (uses forest_runner.yaml)
   forest-runner run
 
@@ -79,43 +145,118 @@ To maintain benchmark integrity, models within a single backend are tested seque
   # Run with parallel backend workers (default concurrency is 1)
   forest-runner run --urls http://ollama-1:11434,http://ollama-2:11434 --concurrency 2
 
+  # Test only models with "coder" in the name
+  forest-runner run --include coder
+
+  # All 7b-class models, but never the 70b ones
+  forest-runner run --include-regex ':\d+b$' --exclude-regex ':70b$'
+
   # Run only specific models
-  forest-runner run --models qwen2.5:7b,llama3.1:8b`,
+  forest-runner run --models qwen2.5:7b,llama3.1:8b
+
+  # Pipe the prompt in from another tool
+  render-prompt --lang go | forest-runner run -p -
+
+  # Template the prompt per model: "Answer as {{.Model}} in {{.lang}}"
+  forest-runner run --prompt-dir ./prompts --var lang=go
+
+  # Quick one-off prompt
+  forest-runner run --prompt "Write a haiku about GPUs"
+
+  # Compare models across a set of prompts (code-gen.md, summarize.txt, ...)
+  forest-runner run --prompt-dir ./prompts
+
+  # Download any of them that a host is missing first
+  forest-runner run --models qwen2.5:7b,llama3.1:8b --pull
+
+  # Also write a SQLite database for ad-hoc queries
+  forest-runner run --outputs csv,json,sqlite
+
+  # Nightly cron: grow one CSV over weeks
+  forest-runner run --outputs csv --csv-append
+
+  # Semicolon-separated CSV with microsecond durations for spreadsheet imports
+  forest-runner run --csv-delimiter ';' --csv-precision 6
+
+  # Bundle each run in results/run-NNNN/, then diff two runs by directory
+  forest-runner run -o ./results --run-dir
+  forest-runner compare ./results/run-0001 ./results/run-0002
+
+  # Lean result files for sharing: performance numbers only, no generated text
+  forest-runner run --include-response=false
+
+  # Produce a standalone HTML report with charts
+  forest-runner run --outputs json,html
+
+  # Authenticate against a secured gateway (token read from the environment)
+  forest-runner run --header 'Authorization=Bearer ${OLLAMA_TOKEN}'
+
+  # Give very large models more time to page into VRAM
+  forest-runner run --models llama3.1:70b --load-timeout 20m
+
+  # Preview what a filtered run would execute before committing hours to it
+  forest-runner run --exclude embed,vision --dry-run
+
+  # Dedicated benchmark rig: no pause between runs
+  forest-runner run --inter-run-delay 0
+
+  # Repeat each config 5 times for mean/median/p95/stddev (first run discarded)
+  forest-runner run --repeat 5
+
+  # Randomized but reproducible model/config order across repeated runs
+  forest-runner run --repeat 5 --shuffle --seed 42
+
+  # Two proxies in front of one GPU box: never more than 2 requests/sec in total
+  forest-runner run --urls http://gpu:11434,http://gpu-proxy:8080 --concurrency 2 --rate-limit 2
+
+  # Pick up an interrupted run where it left off (failed pairs are retried)
+  forest-runner run --resume ./results/model_results.json
+
+  # Watch a long cruise in Grafana (scrape http://host:9090/metrics)
+  forest-runner run --metrics-addr :9090
+
+  # Force-unload after every request to measure cold starts
+  forest-runner run --keep-alive 0
+
+  # Keep each model warm across its configs, but unload before the next model
+  forest-runner run --warmup --unload-after
+
+  # Benchmark a CPU-only host (relax the placement guards)
+  forest-runner run --gpu-only=false --cpu-only-allowed
+
+  # Watch a single model generate in real time
+  forest-runner run --models qwen2.5:7b --interactive
+
+  # Benchmark chat-tuned models through /api/chat
+  forest-runner run --endpoint chat
+
+  # Benchmark embedding models (clear the default "embed" exclusion)
+  forest-runner run --endpoint embeddings --exclude rerank`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// 1. Load Config
-		cfg, err := config.Load(cfgFile)
-		// If err != nil here, it means user specified a file that didn't load, OR
-		// parsing failed. config.Load now handles "no file found" by returning defaults.
+		// 1-2. Load Config, then apply flag overrides.
+		// config.Load returns defaults when no file is found.
+		cfg, err := loadConfig(cmd)
 		if err != nil {
 			return err
 		}
 
-		// 2. Overrides
-		if len(urlsOverride) > 0 {
-			cfg.URLs = urlsOverride
-		}
-		if outputOverride != "" {
-			cfg.OutputDir = outputOverride
-		}
-		if promptFile != "" {
-			data, err := os.ReadFile(promptFile)
-			if err != nil {
-				return fmt.Errorf("failed to read prompt file: %w", err)
-			}
-			cfg.Prompt = string(data)
+		// Likely mistakes (e.g. a typo in inference_configs) are logged; --strict refuses to run
+		warnings := cfg.Warnings()
+		for _, w := range warnings {
+			output.Logger.Warn("Config warning", "field", w.Field, "problem", w.Message)
 		}
-		if len(excludeOverride) > 0 {
-			cfg.Exclude = excludeOverride
-		}
-		if len(modelsOverride) > 0 {
-			cfg.Models = modelsOverride
-		}
-		if cmd.Flags().Changed("concurrency") {
-			cfg.Concurrency = concurrencyOverride
+		if strict && len(warnings) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d config warning(s) with --strict", len(warnings))
 		}
 
-		// 3. Execution
-		return engine.Run(cfg)
+		// 3. Execution (flags are valid at this point; don't print usage on run errors)
+		cmd.SilenceUsage = true
+		if dryRun {
+			return engine.Plan(cmd.Context(), cfg, cmd.OutOrStdout())
+		}
+		engine.ToolVersion = versionString()
+		return engine.Run(cmd.Context(), cfg)
 	},
 }
 
@@ -124,8 +265,132 @@ func init() {
 
 	runCmd.Flags().StringSliceVar(&urlsOverride, "urls", nil, "Comma-separated list of Ollama URLs")
 	runCmd.Flags().StringVarP(&outputOverride, "output-dir", "o", "", "Output directory for results (CSV/JSON)")
-	runCmd.Flags().StringVarP(&promptFile, "prompt-file", "p", "", "Path to a markdown/text file containing the prompt (overrides config)")
+	runCmd.Flags().StringVarP(&promptFile, "prompt-file", "p", "", "Path to a markdown/text file containing the prompt, or - for stdin (overrides config)")
+	runCmd.Flags().StringVar(&promptInline, "prompt", "", "Prompt text (overrides config and --prompt-file)")
+	runCmd.Flags().StringVar(&promptDir, "prompt-dir", "", "Directory of .txt/.md prompts; benchmarks every model against each file")
+	runCmd.Flags().BoolVar(&promptTemplates, "prompt-templates", false, "Render prompts as Go templates ({{.Model}}, {{.URL}}, vars); implied by --var")
+	runCmd.Flags().StringArrayVar(&imagesOverride, "image", nil, "Image file sent with every prompt to vision models (repeatable)")
+	runCmd.Flags().BoolVar(&imageSweep, "image-sweep", false, "Send one image per run instead of all images at once")
+	runCmd.Flags().StringVar(&formatOverride, "format", "", `Structured output: "json" or an inline JSON schema object; responses are checked for valid JSON`)
+	runCmd.Flags().StringSliceVar(&includeOverride, "include", nil, "Comma-separated substrings; only models matching any of them are tested (exclude still applies)")
+	runCmd.Flags().StringArrayVar(&includeRegex, "include-regex", nil, "Only test models matching this Go regexp (repeatable, OR with --include)")
+	runCmd.Flags().StringArrayVar(&excludeRegex, "exclude-regex", nil, "Skip models matching this Go regexp (repeatable)")
 	runCmd.Flags().StringSliceVar(&excludeOverride, "exclude", nil, "Comma-separated list of substrings to exclude from model names")
 	runCmd.Flags().StringSliceVar(&modelsOverride, "models", nil, "Comma-separated list of specific models to run (skips discovery)")
+	runCmd.RegisterFlagCompletionFunc("models", completeModels)
+	runCmd.Flags().StringSliceVar(&outputsOverride, "outputs", nil, "Comma-separated result formats to write: csv, json, json-array, sqlite, markdown, html, influx")
+	runCmd.Flags().BoolVar(&jsonIndent, "json-indent", false, "Pretty-print the json output for reading by eye (debug-only; no longer NDJSON)")
+	runCmd.Flags().BoolVar(&csvAppend, "csv-append", false, "Append CSV rows to <stem>.csv across runs instead of a new numbered file")
+	runCmd.Flags().BoolVar(&runDirLayout, "run-dir", false, "Write this run's outputs into a numbered run-NNNN/ directory with run.meta.json")
+	runCmd.Flags().BoolVar(&includeResponse, "include-response", true, "Keep the generated text in results (--include-response=false to omit it)")
+	runCmd.Flags().IntVar(&responseMaxChars, "response-max-chars", 0, "Truncate kept responses to this many characters (0 = no limit)")
+	runCmd.Flags().StringVar(&csvDelimiter, "csv-delimiter", "", `CSV field separator, a single character (default ",")`)
+	runCmd.Flags().IntVar(&csvPrecision, "csv-precision", 4, "Decimals written for CSV duration columns")
 	runCmd.Flags().IntVarP(&concurrencyOverride, "concurrency", "c", 0, "Number of backend URLs to process in parallel")
+	runCmd.Flags().StringVar(&endpointOverride, "endpoint", "", "API endpoint for metric runs: generate, chat or embeddings")
+	runCmd.Flags().BoolVar(&interactive, "interactive", false, "Print streamed tokens to stdout during the health check")
+	runCmd.Flags().BoolVar(&tokenTimings, "token-timings", false, "Write each streamed token's arrival gap to <stem>.<model>-tokens.jsonl")
+	runCmd.Flags().DurationVar(&overallTimeout, "timeout-overall", 0, "Hard per-request ceiling, replacing load + stream timeout (0 = computed)")
+	runCmd.Flags().DurationVar(&discoveryTimeout, "discovery-timeout", 0, "Limit for each model listing and /api/ps request; slower hosts are skipped (default 10s; 0 disables)")
+	runCmd.Flags().DurationVar(&interRunDelay, "inter-run-delay", 0, "Pause between runs and between models (default 1s; 0 disables)")
+	runCmd.Flags().DurationVar(&loadTimeout, "load-timeout", 0, "Time allowed for a model to load into VRAM (request budget is load + stream timeout)")
+	runCmd.Flags().BoolVar(&gpuOnly, "gpu-only", true, "Abort a model if any part of it spills into system RAM (use --gpu-only=false to allow)")
+	runCmd.Flags().BoolVar(&cpuOnlyAllowed, "cpu-only-allowed", false, "Allow models that load 100% on CPU")
+	runCmd.Flags().Float64Var(&costPerGPUHour, "cost-per-gpu-hour", 0, "GPU price per hour; adds a rough est_cost_per_1m_tokens to results (0 = off)")
+	runCmd.Flags().IntVar(&gpuCount, "gpu-count", 1, "GPUs per host at --cost-per-gpu-hour")
+	runCmd.Flags().Int64Var(&maxVRAMBytes, "max-vram-bytes", 0, "VRAM per host in bytes; skip models that don't fit in the free part before loading (0 = off)")
+	runCmd.Flags().StringVar(&keepAliveOverride, "keep-alive", "", `How long Ollama keeps a model loaded after each request ("0" unloads immediately, "5m", "-1" forever)`)
+	runCmd.Flags().IntVar(&repeatOverride, "repeat", 1, "Run each model/config N times and write aggregated stats (first run is warmup)")
+	runCmd.Flags().IntVar(&modelConcurrency, "model-concurrency", 1, "Number of models to benchmark in parallel per URL (multi-GPU hosts only)")
+	runCmd.Flags().StringVar(&failFast, "fail-fast", "", "What a failure cuts short: config, model or none (default config)")
+	runCmd.Flags().BoolVar(&failFastRun, "fail-fast-run", false, "Abort the whole run on the first failed result (exits non-zero; for CI)")
+	runCmd.Flags().StringVar(&modelOrder, "model-order", "", "Order models are benchmarked in: name, size or discovery (default name)")
+	runCmd.Flags().BoolVar(&strict, "strict", false, "Refuse to run when the config has warnings (e.g. unknown inference options)")
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Discover and filter models, print the planned url x model x config matrix, and exit without running or writing files")
+	runCmd.Flags().BoolVar(&shuffle, "shuffle", false, "Randomize model and config order to average out position bias")
+	runCmd.Flags().Int64Var(&seed, "seed", 0, "Seed for --shuffle; reuse a logged seed to reproduce an order (0 = random)")
+	runCmd.Flags().IntVar(&maxModels, "max-models", 0, "Benchmark at most N models per URL after filtering and ordering (0 = all); pair with --shuffle to sample")
+	runCmd.Flags().IntVar(&globalRateLimit, "rate-limit", 0, "Max inference requests per second across all URLs (0 = unlimited)")
+	runCmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics at this address (e.g. :9090) while the run is in progress")
+	runCmd.Flags().StringVar(&resumePath, "resume", "", "Prior results JSON Lines file; skips model/config pairs that already succeeded")
+	runCmd.Flags().BoolVar(&unloadAfter, "unload-after", false, "Unload each model once its configs finish so every model's load time is measured cold")
+	runCmd.Flags().BoolVar(&autoPull, "pull", false, "Pull --models entries that a host doesn't have before benchmarking")
+	runCmd.Flags().BoolVar(&warmup, "warmup", false, "Load each model with a throwaway request before measured runs (not recorded)")
+	runCmd.Flags().BoolVar(&streamBenchmark, "stream-benchmark", false, "Record the streaming health check as the first config's first run")
+	runCmd.Flags().BoolVar(&noHealthCheck, "no-health-check", false, "Skip the streaming health check and run only the inference configs")
+	runCmd.Flags().BoolVar(&healthCheckOnly, "health-check-only", false, "Run only the streaming health check, one result per model (liveness sweep)")
+	runCmd.Flags().IntSliceVar(&ctxSweep, "ctx-sweep", nil, "Comma-separated num_ctx sizes to sweep; the prompt is padded to fill each")
+	runCmd.Flags().IntVar(&maxTokens, "max-tokens", 0, "Cap generated tokens per request via num_predict (0 = no cap)")
+	runCmd.Flags().BoolVar(&verifyDeterminism, "verify-determinism", false, "Send each run twice and record whether the responses matched (set a seed in inference_configs)")
+	runCmd.Flags().StringVar(&caCertOverride, "cacert", "", "PEM file of a private CA to trust for https backends")
+	runCmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification (self-signed dev boxes only)")
+	runCmd.Flags().StringVar(&proxyOverride, "proxy", "", "Proxy for backend requests: http://, https:// or socks5://host:port")
+	runCmd.Flags().StringArrayVar(&headerOverrides, "header", nil, "Extra request header as key=value (repeatable, supports ${ENV_VAR})")
+	runCmd.Flags().StringArrayVar(&varOverrides, "var", nil, "Prompt template variable as key=value, used as {{.key}} (repeatable)")
+	runCmd.Flags().StringArrayVar(&labelOverrides, "label", nil, "Label copied into every result row as key=value, e.g. experiment=ctx-sweep (repeatable)")
+	runCmd.Flags().StringVar(&runIDOverride, "run-id", "", "ID stamped on every result row (default: a new UUID per run)")
+}
+
+// applyHeaderFlags merges repeated --header key=value flags into the config.
+// Flag values take precedence over headers defined in the config file.
+func applyHeaderFlags(cfg *config.Config, headers []string) error {
+	for _, h := range headers {
+		key, value, ok := strings.Cut(h, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --header %q (expected key=value)", h)
+		}
+		if cfg.Headers == nil {
+			cfg.Headers = make(map[string]string)
+		}
+		cfg.Headers[strings.TrimSpace(key)] = value
+	}
+	return nil
+}
+
+// applyVarFlags merges repeated --var key=value flags into the prompt template variables.
+// Flag values take precedence over vars defined in the config file.
+func applyVarFlags(cfg *config.Config, vars []string) error {
+	for _, v := range vars {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --var %q (expected key=value)", v)
+		}
+		if cfg.Vars == nil {
+			cfg.Vars = make(map[string]string)
+		}
+		cfg.Vars[strings.TrimSpace(key)] = value
+	}
+	return nil
+}
+
+// applyLabelFlags merges repeated --label key=value flags into the result labels.
+// Flag values take precedence over labels defined in the config file.
+func applyLabelFlags(cfg *config.Config, labels []string) error {
+	for _, l := range labels {
+		key, value, ok := strings.Cut(l, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --label %q (expected key=value)", l)
+		}
+		if cfg.Labels == nil {
+			cfg.Labels = make(map[string]string)
+		}
+		cfg.Labels[strings.TrimSpace(key)] = value
+	}
+	return nil
+}
+
+// readPromptFile reads a --prompt-file value; "-" reads all of stdin.
+// A terminal on stdin means nothing was piped in, which would otherwise hang silently.
+func readPromptFile(path string) ([]byte, error) {
+	if path != "-" {
+		return os.ReadFile(path)
+	}
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode()&os.ModeCharDevice != 0 {
+		return nil, fmt.Errorf("stdin is a terminal; pipe a prompt in (e.g. cat prompt.md | forest-runner run -p -)")
+	}
+	return io.ReadAll(os.Stdin)
 }
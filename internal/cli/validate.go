@@ -0,0 +1,81 @@
+/*
+PURPOSE:
+  Defines the 'validate' subcommand.
+  Loads a config file and reports semantic problems without running anything.
+
+REQUIREMENTS:
+  User-specified:
+  - Clear pass/fail summary naming the offending field.
+  - Usable as a CI gate before long benchmark runs.
+
+  Implementation-discovered:
+  - Exit non-zero on failure so pipelines can gate on it.
+  - Warnings (unknown inference options) are printed but only fail with --strict.
+
+ARCHITECTURE INTEGRATION:
+  - Calls: internal/config.Load(), Config.Validate()
+
+ERROR HANDLING:
+  - Returns error on parse failure or when any problem is found.
+
+IMPLEMENTATION RULES:
+  - No network access.
+
+USAGE:
+  forest-runner validate [--config path] [--strict]
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/config/validate.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check a config file for errors without running benchmarks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+
+		problems := cfg.Validate()
+		warnings := cfg.Warnings()
+		if strict {
+			problems = append(problems, warnings...)
+		} else {
+			for _, w := range warnings {
+				fmt.Printf("  WARN %s\n", w.Error())
+			}
+		}
+		if len(problems) == 0 {
+			fmt.Println("PASS: configuration is valid")
+			return nil
+		}
+
+		for _, p := range problems {
+			fmt.Printf("  - %s\n", p.Error())
+		}
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true // main prints the returned error
+		return fmt.Errorf("FAIL: %d problem(s) found", len(problems))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVar(&strict, "strict", false, "Treat warnings (e.g. unknown inference options) as errors")
+}
@@ -0,0 +1,72 @@
+/*
+PURPOSE:
+  Defines the 'version' subcommand and build metadata.
+  Identifies which build produced a given set of benchmark results.
+
+REQUIREMENTS:
+  User-specified:
+  - Print version, git commit, and build date.
+  - Support `--version` on the root command.
+
+  Implementation-discovered:
+  - Values are injected at build time via -ldflags -X.
+  - The version also names the default User-Agent (forest-runner/<version>).
+
+ARCHITECTURE INTEGRATION:
+  - Populated by: Makefile, .github/workflows/release.yml
+
+ERROR HANDLING:
+  - None.
+
+IMPLEMENTATION RULES:
+  - Keep Version/Commit/Date as plain package-level strings (required by -X).
+
+USAGE:
+  go build -ldflags "-X github.com/daryltucker/forest-runner/internal/cli.Version=v1.2.3" ./cmd/forest-runner
+  forest-runner version
+
+SELF-HEALING INSTRUCTIONS:
+  - If values show "dev"/"unknown", the binary was built without ldflags.
+
+RELATED FILES:
+  - Makefile
+
+MAINTENANCE:
+  - None.
+*/
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/daryltucker/forest-runner/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, injected via -ldflags -X at build time.
+var (
+	Version = "dev"
+	Commit  = "unknown"
+	Date    = "unknown"
+)
+
+// versionString formats the build metadata for display.
+func versionString() string {
+	return fmt.Sprintf("forest-runner %s (commit %s, built %s)", Version, Commit, Date)
+}
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version and build information",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(versionString())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.Version = Version
+	engine.DefaultUserAgent = "forest-runner/" + Version
+	rootCmd.SetVersionTemplate(versionString() + "\n")
+}
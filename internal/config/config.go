@@ -41,57 +41,428 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/daryltucker/forest-runner/internal/model"
 	"gopkg.in/yaml.v3"
 )
 
+// Supported API endpoints for metric runs.
+const (
+	EndpointGenerate   = "generate"   // /api/generate (raw completion)
+	EndpointChat       = "chat"       // /api/chat (conversational)
+	EndpointEmbeddings = "embeddings" // /api/embeddings (vector latency)
+)
+
+// Supported backend protocols.
+const (
+	ProtocolOllama = "ollama" // Native Ollama API (/api/...)
+	ProtocolOpenAI = "openai" // OpenAI-compatible API (/v1/...), e.g. LiteLLM or vLLM
+)
+
+// Supported result output formats.
+const (
+	OutputCSV       = "csv"        // <stem>.csv (spreadsheets)
+	OutputJSON      = "json"       // <stem>.json, JSON Lines (jq/vecq, --resume)
+	OutputJSONArray = "json-array" // <stem>_array.json, one JSON array (jq without -s, BI importers)
+	OutputSQLite    = "sqlite"     // <stem>.db (ad-hoc SQL)
+	OutputMarkdown  = "markdown"   // <stem>.md (table written at the end of the run)
+	OutputHTML      = "html"       // <stem>.html (standalone report with charts)
+	OutputInflux    = "influx"     // <stem>.lp, InfluxDB line protocol (time-series dashboards)
+)
+
+// Supported model orderings.
+const (
+	ModelOrderName      = "name"      // Alphabetical (deterministic rows, clean diffs)
+	ModelOrderSize      = "size"      // Smallest first, by /api/tags size
+	ModelOrderDiscovery = "discovery" // As returned by the server or listed in models
+)
+
+// Supported fail_fast levels: what a failure cuts short.
+const (
+	FailFastConfig = "config" // A failed config ends the model's remaining configs
+	FailFastModel  = "model"  // Also a failed health check skips the model
+	FailFastNone   = "none"   // Nothing is skipped; every error is recorded
+)
+
 // Config represents the full configuration for Forest Runner.
 type Config struct {
 	URLs           []string      `yaml:"urls"`
 	Prompt         string        `yaml:"prompt"`
+	PromptDir      string        `yaml:"prompt_dir"` // Every .txt/.md file here is benchmarked as a prompt; replaces prompt
 	OutputDir      string        `yaml:"output_dir"`
-	OutputFile     string        `yaml:"output_file"` // Deprecated? Or just filename? Let's keep for filename base.
+	OutputFile     string        `yaml:"output_file"` // Its stem names every output: model_results.csv -> model_results.{csv,json,...}
 	MaxRetries     int           `yaml:"max_retries"`
 	RetryDelay     time.Duration `yaml:"retry_delay"`
 	StreamTimeout  time.Duration `yaml:"stream_timeout"`
-	LoadTimeout    time.Duration `yaml:"load_timeout"`
-	KeepAlive      string        `yaml:"keep_alive"` // "0", "5m", etc.
+	LoadTimeout    time.Duration `yaml:"load_timeout"` // Per-request budget is LoadTimeout + StreamTimeout
+	KeepAlive      string        `yaml:"keep_alive"`   // Ollama duration string: "0" (unload now), "5m", "1h", "-1" (forever)
 	CPUOnlyAllowed bool          `yaml:"cpu_only_allowed"`
 	GPUOnly        bool          `yaml:"gpu_only"`
-	// Exclude is a list of strings to filter model names (substring match)
+	// MaxVRAMBytes is the VRAM capacity of each Ollama host. When set, a model
+	// whose size exceeds it minus what /api/ps reports as in use is skipped with
+	// a vram_headroom error instead of loaded (0 = no pre-load check)
+	MaxVRAMBytes int64 `yaml:"max_vram_bytes"`
+	// CostPerGPUHour is what one GPU costs per hour (any currency). When set,
+	// results carry a rough cost per 1M generated tokens (0 = no estimate)
+	CostPerGPUHour float64 `yaml:"cost_per_gpu_hour"`
+	// GPUCount is how many GPUs of that price serve each host
+	GPUCount int `yaml:"gpu_count"`
+	// OverallTimeout is a hard per-request ceiling that replaces the computed
+	// LoadTimeout + StreamTimeout budget (0 = computed)
+	OverallTimeout time.Duration `yaml:"overall_timeout"`
+	// DiscoveryTimeout bounds each model listing and /api/ps request, so a dead
+	// host is skipped quickly instead of after the inference budget (0 = no separate limit)
+	DiscoveryTimeout time.Duration `yaml:"discovery_timeout"`
+	// InterRunDelay is the pause between measured runs and between models on a worker
+	// (0 on a dedicated rig; longer on shared hardware for thermal recovery)
+	InterRunDelay time.Duration `yaml:"inter_run_delay"`
+	// ModelTimeouts replaces the per-request budget for models whose name
+	// contains the key (case-insensitive), e.g. {"70b": 5m, "1b": 20s}.
+	// The longest matching key wins; no match uses the global budget
+	ModelTimeouts map[string]time.Duration `yaml:"model_timeouts"`
+	// RetryDeadline caps the wall-clock time a request keeps retrying: no new
+	// attempt starts after it (0 = max_retries alone decides)
+	RetryDeadline time.Duration `yaml:"retry_deadline"`
+	// FailFast is what a failure cuts short: "config" (the model's remaining
+	// configs), "model" (also skip a model whose health check fails) or "none"
+	FailFast string `yaml:"fail_fast"`
+	// FailFastRun aborts the whole run on the first failed result (CI gating)
+	FailFastRun bool `yaml:"fail_fast_run"`
+	// MonitorInterval is how often /api/ps is polled to enforce the GPU/CPU guards while a model loads
+	MonitorInterval time.Duration `yaml:"monitor_interval"`
+	// Include keeps only models whose name contains one of these substrings (OR); empty keeps all
+	Include []string `yaml:"include"`
+	// Exclude is a list of strings to filter model names (substring match), applied after Include
 	Exclude []string `yaml:"exclude"`
-	// Models is an optional list of specific model names to include (overrides discovery)
+	// IncludeRegex and ExcludeRegex are Go regexps for filters substrings can't express (e.g. ":\d+b$")
+	IncludeRegex []string `yaml:"include_regex"`
+	ExcludeRegex []string `yaml:"exclude_regex"`
+	// Models is an optional list of specific model names to include (overrides discovery).
+	// Exclude filters still apply to this list; --models replaces it entirely.
 	Models []string `yaml:"models"`
 	// InferConfigs allows defining multiple inference configurations
 	InferConfigs []map[string]interface{} `yaml:"inference_configs"`
 	// Concurrency defines how many backend URLs to process in parallel
 	Concurrency int `yaml:"concurrency"`
+	// Endpoint selects the Ollama API used for metric runs ("generate", "chat" or "embeddings")
+	Endpoint string `yaml:"endpoint"`
+	// Messages is optional conversation context sent before the prompt in chat mode
+	Messages []model.Message `yaml:"messages"`
+	// Images are image files sent with every generate request (the "images"
+	// array) to benchmark vision models; models without vision are skipped
+	Images []string `yaml:"images"`
+	// ImageSweep sends one image per run instead of all of them together;
+	// rows are named <prompt>+<image file>
+	ImageSweep bool `yaml:"image_sweep"`
+	// Format requests structured output from Ollama: "json", or a JSON schema
+	// object (newer Ollama). Responses are checked for valid JSON ("" = off)
+	Format interface{} `yaml:"format"`
+	// Protocol is the default API dialect spoken to backends ("ollama" or "openai")
+	Protocol string `yaml:"protocol"`
+	// URLProtocols overrides Protocol for specific backend URLs
+	URLProtocols map[string]string `yaml:"url_protocols"`
+	// Interactive echoes streamed tokens to stdout during the health check
+	Interactive bool `yaml:"interactive"`
+	// TokenTimings writes every streamed token's arrival gap to
+	// <base>.<model>-tokens.jsonl (streaming only; large output)
+	TokenTimings bool `yaml:"token_timings"`
+	// Headers are added to every outbound request; values support ${ENV_VAR} expansion
+	Headers map[string]string `yaml:"headers"`
+	// UserAgent replaces the default "forest-runner/<version>" User-Agent
+	UserAgent string `yaml:"user_agent"`
+	// CACertFile is a PEM bundle trusted for https backends, on top of the system roots
+	CACertFile string `yaml:"ca_cert_file"`
+	// InsecureSkipVerify disables TLS certificate verification (self-signed dev boxes only)
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// ClientCertFile and ClientKeyFile present a client certificate (mTLS); set both or neither
+	ClientCertFile string `yaml:"client_cert_file"`
+	ClientKeyFile  string `yaml:"client_key_file"`
+	// Proxy routes backend requests through http://, https:// or socks5:// proxy
+	// (empty = HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment)
+	Proxy string `yaml:"proxy"`
+	// CtxSweep generates one inference config per size with num_ctx set to it
+	// (crossed with inference_configs) and pads the prompt to roughly fill it
+	CtxSweep []int `yaml:"ctx_sweep"`
+	// MaxTokens caps generated tokens per request: injected as "num_predict" into
+	// every inference config that doesn't set one. An upper bound, not a target;
+	// models may stop earlier (0 = no cap)
+	MaxTokens int `yaml:"max_tokens"`
+	// Repeat runs each (model, config) pair N times; N > 1 also writes a summary file
+	Repeat int `yaml:"repeat"`
+	// Warmup sends a throwaway request before measured runs so load time doesn't skew them
+	Warmup bool `yaml:"warmup"`
+	// VerifyDeterminism sends every measured run twice and records whether the
+	// responses matched (pair with a fixed "seed" in inference_configs)
+	VerifyDeterminism bool `yaml:"verify_determinism"`
+	// StreamBenchmark sends the first config with the streaming health check and
+	// records it as that config's first run, saving one request per model
+	StreamBenchmark bool `yaml:"stream_benchmark"`
+	// SkipHealthCheck drops the per-model streaming health check and goes straight
+	// to the metric runs (no TTFT; the first run pays the model load)
+	SkipHealthCheck bool `yaml:"skip_health_check"`
+	// HealthCheckOnly runs just the streaming health check per model and records
+	// it as the model's result: a quick fleet liveness sweep
+	HealthCheckOnly bool `yaml:"health_check_only"`
+	// ModelConcurrency is how many models run in parallel against one URL.
+	// Concurrent loads can exceed VRAM, so only raise this on multi-GPU hosts.
+	ModelConcurrency int `yaml:"model_concurrency"`
+	// ModelOrder is the order models are benchmarked in per URL ("name", "size" or "discovery")
+	ModelOrder string `yaml:"model_order"`
+	// Shuffle randomizes model and config order per URL so position bias
+	// (e.g. thermal throttling late in a run) averages out over repeated runs
+	Shuffle bool `yaml:"shuffle"`
+	// Seed makes Shuffle reproducible (0 = pick one at random and log it)
+	Seed int64 `yaml:"seed"`
+	// MaxModels caps how many models are benchmarked per URL, after filtering
+	// and ordering; with Shuffle this samples a large host (0 = unlimited)
+	MaxModels int `yaml:"max_models"`
+	// URLModelConcurrency overrides ModelConcurrency for specific backend URLs
+	URLModelConcurrency map[string]int `yaml:"url_model_concurrency"`
+	// GlobalRateLimit caps inference requests per second across all URLs (0 = unlimited).
+	// Useful when several URLs are proxies for the same GPU box.
+	GlobalRateLimit int `yaml:"global_rate_limit"`
+	// Resume is a prior JSON Lines result file; tuples that succeeded there are skipped
+	Resume string `yaml:"resume"`
+	// UnloadAfter evicts each model (keep_alive 0) once its configs finish, so every model starts cold
+	UnloadAfter bool `yaml:"unload_after"`
+	// AutoPull downloads explicitly listed models that a host doesn't have (via /api/pull)
+	AutoPull bool `yaml:"auto_pull"`
+	// Outputs lists the result formats to write ("csv", "json", "json-array", "sqlite", "markdown", "html", "influx")
+	Outputs []string `yaml:"outputs"`
+	// JSONIndent pretty-prints the json output with a blank line between results.
+	// Debug-only: the file is no longer strict NDJSON (one object per line)
+	JSONIndent bool `yaml:"json_indent"`
+	// CSVAppend appends rows to <stem>.csv across runs instead of writing a new numbered file
+	CSVAppend bool `yaml:"csv_append"`
+	// CSVDelimiter separates CSV fields; must be a single character (e.g. ";" for German locales)
+	CSVDelimiter string `yaml:"csv_delimiter"`
+	// CSVPrecision is the number of decimals written for CSV duration columns
+	CSVPrecision int `yaml:"csv_precision"`
+	// IncludeResponse keeps the generated text in results; false writes an empty response
+	IncludeResponse bool `yaml:"include_response"`
+	// ResponseMaxChars truncates kept responses to this many characters (0 = no limit)
+	ResponseMaxChars int `yaml:"response_max_chars"`
+	// MetricsAddr, when set (e.g. ":9090"), serves live Prometheus metrics at /metrics during the run
+	MetricsAddr string `yaml:"metrics_addr"`
+	// RunDirLayout writes each run's outputs into a numbered <output_dir>/run-NNNN/ directory
+	RunDirLayout bool `yaml:"run_dir_layout"`
+	// PromptTemplates renders prompts as Go templates ({{.Model}}, {{.URL}}, vars).
+	// Off by default so prompts with literal braces are sent verbatim; setting
+	// any vars turns it on
+	PromptTemplates bool `yaml:"prompt_templates"`
+	// Vars are extra prompt template variables ({{.name}}); Model and URL are built in
+	Vars map[string]string `yaml:"vars"`
+	// Labels are copied into every result row (e.g. experiment: ctx-sweep) so
+	// pooled result files can still be sliced by experiment or rig
+	Labels map[string]string `yaml:"labels"`
+	// RunID tags every result row of one invocation (empty = a new UUID per run).
+	// Set it to keep one ID across invocations, e.g. a resumed run
+	RunID string `yaml:"run_id"`
 }
 
 // DefaultConfig returns the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		URLs:           []string{"http://localhost:11434"},
-		Prompt:         "What is the capital of France?",
-		OutputDir:      ".",
-		OutputFile:     "model_results.csv",
-		MaxRetries:     3,
-		RetryDelay:     2 * time.Second,
-		StreamTimeout:  60 * time.Second,
-		LoadTimeout:    10 * time.Minute,
-		KeepAlive:      "10s",
-		CPUOnlyAllowed: false,
-		GPUOnly:        true,
-		Exclude:        []string{"embed", "rerank"},
+		URLs:             []string{"http://localhost:11434"},
+		Prompt:           "What is the capital of France?",
+		OutputDir:        ".",
+		OutputFile:       "model_results.csv",
+		MaxRetries:       3,
+		RetryDelay:       2 * time.Second,
+		StreamTimeout:    60 * time.Second,
+		LoadTimeout:      10 * time.Minute,
+		DiscoveryTimeout: 10 * time.Second,
+		KeepAlive:        "5m",
+		CPUOnlyAllowed:   false,
+		GPUOnly:          true,
+		MonitorInterval:  2 * time.Second,
+		InterRunDelay:    1 * time.Second,
+		Exclude:          []string{"embed", "rerank"},
 		InferConfigs: []map[string]interface{}{
 			{"num_ctx": 2048},
 			{"num_ctx": 4096},
 		},
 		Concurrency: 1,
+		Endpoint:    EndpointGenerate,
+		Protocol:    ProtocolOllama,
+		Repeat:      1,
+
+		ModelConcurrency: 1,
+		GPUCount:         1,
+		ModelOrder:       ModelOrderName,
+		FailFast:         FailFastConfig,
+		Outputs:          []string{OutputCSV, OutputJSON},
+		CSVDelimiter:     ",",
+		CSVPrecision:     4,
+		IncludeResponse:  true,
 	}
 }
 
+// HasOutput reports whether the given result format is enabled.
+func (c *Config) HasOutput(format string) bool {
+	for _, o := range c.Outputs {
+		if strings.EqualFold(o, format) {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVComma returns csv_delimiter as a rune for encoding/csv.
+// It must be exactly one character and not a quote, newline or carriage return.
+func (c *Config) CSVComma() (rune, error) {
+	if utf8.RuneCountInString(c.CSVDelimiter) != 1 {
+		return 0, fmt.Errorf("csv_delimiter must be a single character (got %q)", c.CSVDelimiter)
+	}
+	r, _ := utf8.DecodeRuneInString(c.CSVDelimiter)
+	switch r {
+	case '"', '\r', '\n', utf8.RuneError:
+		return 0, fmt.Errorf("csv_delimiter %q cannot be used as a CSV separator", c.CSVDelimiter)
+	}
+	return r, nil
+}
+
+// Redacted returns a copy of the config that is safe to write next to results:
+// header values (tokens, API keys) are replaced with "***", as are passwords in
+// the userinfo of backend URLs, per-URL override keys and the proxy.
+func (c *Config) Redacted() *Config {
+	out := *c
+	if c.Headers != nil {
+		out.Headers = make(map[string]string, len(c.Headers))
+		for k := range c.Headers {
+			out.Headers[k] = "***"
+		}
+	}
+	if c.URLs != nil {
+		out.URLs = make([]string, len(c.URLs))
+		for i, u := range c.URLs {
+			out.URLs[i] = RedactURL(u)
+		}
+	}
+	if c.URLProtocols != nil {
+		out.URLProtocols = make(map[string]string, len(c.URLProtocols))
+		for u, p := range c.URLProtocols {
+			out.URLProtocols[RedactURL(u)] = p
+		}
+	}
+	if c.URLModelConcurrency != nil {
+		out.URLModelConcurrency = make(map[string]int, len(c.URLModelConcurrency))
+		for u, n := range c.URLModelConcurrency {
+			out.URLModelConcurrency[RedactURL(u)] = n
+		}
+	}
+	out.Proxy = RedactURL(c.Proxy)
+	return &out
+}
+
+// WriteRedacted saves the redacted config as YAML that can be fed back to --config.
+// comment is written first as "# " lines (e.g. tool version and timestamp).
+func (c *Config) WriteRedacted(path, comment string) error {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err
+	}
+	var buf strings.Builder
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString("# " + line + "\n")
+	}
+	buf.Write(data)
+	return os.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+// Snapshot returns the redacted config as a generic map keyed by YAML field
+// names, for embedding in JSON metadata.
+func (c *Config) Snapshot() (map[string]interface{}, error) {
+	data, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ProtocolFor returns the protocol configured for a backend URL,
+// falling back to the global Protocol when no per-URL override exists.
+func (c *Config) ProtocolFor(url string) string {
+	if p, ok := c.URLProtocols[url]; ok && p != "" {
+		return p
+	}
+	return c.Protocol
+}
+
+// RequestBudgetFor returns the deadline for one inference attempt against
+// modelName: the longest model_timeouts key contained in the name (ties go to
+// the alphabetically first key), else overall_timeout if set, else
+// load_timeout + stream_timeout.
+func (c *Config) RequestBudgetFor(modelName string) time.Duration {
+	name := strings.ToLower(modelName)
+	best, budget := "", time.Duration(0)
+	for pattern, d := range c.ModelTimeouts {
+		p := strings.ToLower(pattern)
+		if !strings.Contains(name, p) {
+			continue
+		}
+		if budget == 0 || len(p) > len(best) || (len(p) == len(best) && p < best) {
+			best, budget = p, d
+		}
+	}
+	if budget > 0 {
+		return budget
+	}
+	if c.OverallTimeout > 0 {
+		return c.OverallTimeout
+	}
+	return c.LoadTimeout + c.StreamTimeout
+}
+
+// LongestRequestBudget is the largest budget any model can get, for limits
+// that are set once per client rather than per request.
+func (c *Config) LongestRequestBudget() time.Duration {
+	longest := c.RequestBudgetFor("")
+	for _, d := range c.ModelTimeouts {
+		longest = max(longest, d)
+	}
+	return longest
+}
+
+// CostPer1MTokens estimates the hardware cost of generating one million tokens
+// at tokensPerSec on GPUCount GPUs billed at CostPerGPUHour. 0 when no cost is
+// configured or there is no rate.
+func (c *Config) CostPer1MTokens(tokensPerSec float64) float64 {
+	if c.CostPerGPUHour <= 0 || tokensPerSec <= 0 {
+		return 0
+	}
+	gpus := max(c.GPUCount, 1)
+	return c.CostPerGPUHour * float64(gpus) / (tokensPerSec * 3600) * 1e6
+}
+
+// TemplatePrompts reports whether prompts are rendered as templates:
+// prompt_templates is set or vars are configured.
+func (c *Config) TemplatePrompts() bool {
+	return c.PromptTemplates || len(c.Vars) > 0
+}
+
+// StructuredOutput reports whether a format ("json" or a schema) is configured.
+func (c *Config) StructuredOutput() bool {
+	return c.Format != nil && c.Format != ""
+}
+
+// ModelConcurrencyFor returns how many models may run in parallel against a URL,
+// falling back to the global ModelConcurrency when no per-URL cap exists.
+func (c *Config) ModelConcurrencyFor(url string) int {
+	if n, ok := c.URLModelConcurrency[url]; ok && n > 0 {
+		return n
+	}
+	return c.ModelConcurrency
+}
+
 // Load reads configuration from a file.
 // If path is specified, it attempts to load that file.
 // If path is empty, it searches for default files in order.
@@ -120,7 +491,10 @@ func Load(path string) (*Config, error) {
 			}
 		}
 		if !found {
-			// No config file found, return default
+			// No config file found, use defaults (env may still override)
+			if err := applyEnv(cfg); err != nil {
+				return nil, err
+			}
 			return cfg, nil
 		}
 	}
@@ -129,5 +503,70 @@ func Load(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
 	}
 
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// applyEnv overrides config values from FOREST_* environment variables.
+// Precedence is: CLI flags > environment > config file > defaults.
+func applyEnv(cfg *Config) error {
+	if v := os.Getenv("FOREST_URLS"); v != "" {
+		var urls []string
+		for _, u := range strings.Split(v, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				urls = append(urls, u)
+			}
+		}
+		cfg.URLs = urls
+	}
+	if v := os.Getenv("FOREST_OUTPUT_DIR"); v != "" {
+		cfg.OutputDir = v
+	}
+	if v := os.Getenv("FOREST_PROMPT"); v != "" {
+		cfg.Prompt = v
+	}
+
+	ints := []struct {
+		name string
+		dst  *int
+	}{
+		{"FOREST_CONCURRENCY", &cfg.Concurrency},
+		{"FOREST_MAX_RETRIES", &cfg.MaxRetries},
+	}
+	for _, iv := range ints {
+		if v := os.Getenv(iv.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%q: %w", iv.name, v, err)
+			}
+			*iv.dst = n
+		}
+	}
+
+	durations := []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"FOREST_RETRY_DELAY", &cfg.RetryDelay},
+		{"FOREST_RETRY_DEADLINE", &cfg.RetryDeadline},
+		{"FOREST_STREAM_TIMEOUT", &cfg.StreamTimeout},
+		{"FOREST_LOAD_TIMEOUT", &cfg.LoadTimeout},
+		{"FOREST_OVERALL_TIMEOUT", &cfg.OverallTimeout},
+		{"FOREST_DISCOVERY_TIMEOUT", &cfg.DiscoveryTimeout},
+		{"FOREST_INTER_RUN_DELAY", &cfg.InterRunDelay},
+	}
+	for _, dv := range durations {
+		if v := os.Getenv(dv.name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%q: %w", dv.name, v, err)
+			}
+			*dv.dst = d
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,312 @@
+/*
+PURPOSE:
+  Renders a fully-commented example configuration file from DefaultConfig.
+  Serves as living documentation of the config schema.
+
+REQUIREMENTS:
+  User-specified:
+  - `forest-runner init` writes every config key with an explanatory comment.
+
+  Implementation-discovered:
+  - Values come from DefaultConfig() so the scaffold never drifts from real defaults.
+  - Lists/maps are rendered through yaml.Marshal to guarantee valid YAML.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/init.go
+  - Uses: Config, DefaultConfig
+
+ERROR HANDLING:
+  - Returns template/marshal errors to the caller.
+
+IMPLEMENTATION RULES:
+  - Every field in Config must appear in scaffoldTemplate.
+
+USAGE:
+  data, err := config.Scaffold()
+
+SELF-HEALING INSTRUCTIONS:
+  - If a key is missing from generated files, add it to scaffoldTemplate.
+
+RELATED FILES:
+  - internal/config/config.go
+
+MAINTENANCE:
+  - Update scaffoldTemplate whenever a Config field is added.
+*/
+
+package config
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+const scaffoldTemplate = `# Forest Runner Configuration
+# Generated by 'forest-runner init'. Every key is optional; omitted keys use the defaults shown here.
+
+# Ollama (or OpenAI-compatible) backends to benchmark; unix:///path/to/ollama.sock for sockets
+urls:
+{{- range .URLs}}
+  - {{quote .}}
+{{- end}}
+
+# Prompt sent to every model (overridden by --prompt / --prompt-file)
+prompt: {{quote .Prompt}}
+
+# Directory of .txt/.md prompts; each model is benchmarked against every file
+# and rows are labelled with the file name (replaces prompt when set)
+prompt_dir: {{quote .PromptDir}}
+
+# Image files sent with every prompt to benchmark vision models (e.g. llava).
+# Generate endpoint on Ollama only; models whose /api/show lacks vision are skipped
+images:{{yaml .Images}}
+# Send one image per run instead of all together; rows are named <prompt>+<image>
+image_sweep: {{.ImageSweep}}
+
+# Structured output: "json", or a JSON schema object (newer Ollama), e.g.
+#   format: {type: object, properties: {answer: {type: string}}, required: [answer]}
+# Each response is checked for valid JSON (valid_json in results). Ollama only
+format: ""
+
+# Render prompts as Go templates: {{"{{.Model}}"}} and {{"{{.URL}}"}} are built in, and every
+# vars key is available as {{"{{.key}}"}}, e.g. lang: go -> "Write it in {{"{{.lang}}"}}".
+# Off by default so literal braces (Jinja, Helm, JSON) are sent as written;
+# setting any vars turns it on
+prompt_templates: {{.PromptTemplates}}
+vars:{{yaml .Vars}}
+
+# Labels copied into every result row, e.g. experiment: ctx-sweep, host: rig-2,
+# so pooled result files can be sliced later (also settable with --label key=value)
+labels:{{yaml .Labels}}
+# ID stamped on every result row; empty = a new UUID per run. Set it to keep one
+# ID across invocations, e.g. a resumed run
+run_id: {{quote .RunID}}
+
+# Output location. The output_file stem names every result file of a run
+# (model_results.csv, model_results.json, ...). Existing results are never
+# overwritten: later runs use a shared number (model_results-1.csv + model_results-1.json).
+output_dir: {{quote .OutputDir}}
+output_file: {{quote .OutputFile}}
+# Result formats: csv, json (JSON Lines), json-array (_array.json, one JSON array),
+# sqlite (.db), markdown (.md), html (.html with charts), influx (.lp, InfluxDB
+# line protocol; load with "influx write -f").
+# markdown and html are written once, when the run ends.
+outputs:{{yaml .Outputs}}
+# Keep the generated text in results (false for lean, shareable files), optionally
+# truncated to response_max_chars characters (0 = no limit)
+include_response: {{.IncludeResponse}}
+response_max_chars: {{.ResponseMaxChars}}
+# Put each run's files in a numbered <output_dir>/run-NNNN/ directory together with
+# run.meta.json (timestamp, URLs, config with header values redacted)
+run_dir_layout: {{.RunDirLayout}}
+# Pretty-print the json output (indented, blank line between results) for
+# eyeballing a few runs. Debug-only: the file is no longer one result per line
+json_indent: {{.JSONIndent}}
+# Append CSV rows to <stem>.csv on every run (header written once) instead of
+# starting a new numbered file; handy for nightly benchmarks tracked over time
+csv_append: {{.CSVAppend}}
+# CSV field separator (e.g. ";" for German-locale spreadsheets) and decimals for duration columns
+csv_delimiter: {{quote .CSVDelimiter}}
+csv_precision: {{.CSVPrecision}}
+
+# Timeouts & Retries (Go durations, e.g. 2s, 1m, 10m)
+max_retries: {{.MaxRetries}}
+retry_delay: {{.RetryDelay}}
+retry_deadline: {{.RetryDeadline}}  # No new attempt after this much time since the first (0 = max_retries only)
+stream_timeout: {{.StreamTimeout}}
+load_timeout: {{.LoadTimeout}}  # Time allowed for model load; each request may take load + stream timeout
+overall_timeout: {{.OverallTimeout}}  # Hard ceiling per request; 0 = load_timeout + stream_timeout
+discovery_timeout: {{.DiscoveryTimeout}}  # Per model-listing / /api/ps request; dead hosts are skipped after this
+inter_run_delay: {{.InterRunDelay}}  # Pause between runs and between models (0 on a dedicated rig)
+# Per-model request budget replacing the above, keyed by model-name substring,
+# e.g. {"70b": 5m, "1b": 20s}; the longest matching key wins
+model_timeouts:{{yaml .ModelTimeouts}}
+
+# What a failure cuts short: config (the model's remaining configs), model (also
+# skip a model whose health check fails) or none (run everything, record all errors)
+fail_fast: {{quote .FailFast}}
+# Abort the whole run on the first failed result, e.g. to gate CI
+fail_fast_run: {{.FailFastRun}}
+
+# Strict Hardware Guards
+gpu_only: {{.GPUOnly}}  # Abort if any part of the model spills into system RAM
+cpu_only_allowed: {{.CPUOnlyAllowed}}  # Allow models that load 100% on CPU
+# VRAM per Ollama host in bytes (24 GiB = 25769803776). When set, models larger
+# than its free part (per /api/ps) are skipped instead of loaded; 0 = off
+max_vram_bytes: {{.MaxVRAMBytes}}
+
+# Rough cost estimate: price of one GPU per hour (any currency) and GPUs per host.
+# When set, results get est_cost_per_1m_tokens from the measured generation rate
+# of a single request; batched serving costs less. 0 = no estimate
+cost_per_gpu_hour: {{.CostPerGPUHour}}
+gpu_count: {{.GPUCount}}
+keep_alive: {{quote .KeepAlive}}  # Ollama duration string: "0" unloads immediately, "5m", "-1" forever
+# How often /api/ps is polled to enforce the guards while a model loads.
+# Not polled at all when gpu_only is false and cpu_only_allowed is true.
+monitor_interval: {{.MonitorInterval}}
+
+# Backend Concurrency: number of backend URLs processed in parallel
+concurrency: {{.Concurrency}}
+# Models benchmarked in parallel per URL. Concurrent loads can exceed VRAM;
+# only raise this on multi-GPU hosts.
+model_concurrency: {{.ModelConcurrency}}
+# Per-URL overrides of model_concurrency, e.g. "http://big-box:11434": 4
+url_model_concurrency:{{yaml .URLModelConcurrency}}
+# Max inference requests per second across ALL URLs (0 = unlimited). Use when
+# several URLs share one GPU box. Retries wait retry_delay, then for the limiter.
+global_rate_limit: {{.GlobalRateLimit}}
+
+# Benchmark order per URL: name (alphabetical), size (smallest first) or
+# discovery (as the server lists them / as written in models)
+model_order: {{quote .ModelOrder}}
+
+# Randomize model and config order so position-dependent bias (thermal
+# throttling builds up over a run) averages out across repeated runs.
+# A non-zero seed makes the order reproducible; 0 picks one and logs it.
+shuffle: {{.Shuffle}}
+seed: {{.Seed}}
+
+# Benchmark at most this many models per URL, after filters and ordering
+# (0 = all); with shuffle this is a random sample for a quick spot-check
+max_models: {{.MaxModels}}
+
+# Model Selection
+# include: case-insensitive substrings; if set, only models matching any of them are tested
+include:{{yaml .Include}}
+# exclude: case-insensitive substrings; matching models are skipped (applied after include)
+exclude:{{yaml .Exclude}}
+# include_regex / exclude_regex: Go regular expressions (case-sensitive; use (?i) to ignore case),
+# combined with the substring lists above, e.g. exclude_regex: [":70b$"]
+include_regex:{{yaml .IncludeRegex}}
+exclude_regex:{{yaml .ExcludeRegex}}
+# models: explicit list that skips discovery (exclude still applies)
+models:{{yaml .Models}}
+# Pull listed models that a host doesn't have yet (ollama protocol only)
+auto_pull: {{.AutoPull}}
+
+# Inference option sets; each model is benchmarked once per entry
+inference_configs:{{yaml .InferConfigs}}
+
+# Context size sweep, e.g. [2048, 4096, 8192, 16384]: each inference config is
+# run once per size with num_ctx set to it, and the prompt is padded with filler
+# to roughly fill the context. Plot tokens/sec against prompt_eval_count
+ctx_sweep:{{yaml .CtxSweep}}
+
+# Cap generated tokens per request: added as "num_predict" to every inference
+# config that doesn't set one (0 = no cap). This is an upper bound; a model that
+# finishes its answer sooner stops sooner
+max_tokens: {{.MaxTokens}}
+
+# API endpoint for metric runs: generate, chat or embeddings
+endpoint: {{quote .Endpoint}}
+# Conversation context sent before the prompt (chat endpoint / openai protocol)
+messages:{{yaml .Messages}}
+
+# Backend protocol: ollama or openai (/v1/chat/completions)
+protocol: {{quote .Protocol}}
+# Per-URL protocol overrides, e.g. "http://vllm:8000": openai
+url_protocols:{{yaml .URLProtocols}}
+
+# Print streamed tokens to stdout during the health check
+interactive: {{.Interactive}}
+# Write each streamed token's arrival gap (ms) to <stem>.<model>-tokens.jsonl for
+# inter-token latency analysis. Streaming requests only; produces a lot of data
+token_timings: {{.TokenTimings}}
+
+# Extra request headers; values support ${ENV_VAR} expansion
+# e.g. Authorization: "Bearer ${OLLAMA_TOKEN}"
+headers:{{yaml .Headers}}
+
+# User-Agent sent on every request (empty = forest-runner/<version>), so load on
+# shared boxes can be attributed in the backend's logs
+user_agent: {{quote .UserAgent}}
+
+# TLS for https backends: a private CA (PEM), skipping verification (dev only),
+# and a client certificate/key pair for mTLS
+ca_cert_file: {{quote .CACertFile}}
+insecure_skip_verify: {{.InsecureSkipVerify}}
+client_cert_file: {{quote .ClientCertFile}}
+client_key_file: {{quote .ClientKeyFile}}
+
+# Proxy for backend requests: http://, https:// or socks5://host:port
+# (empty = HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment)
+proxy: {{quote .Proxy}}
+
+# Run each (model, config) pair N times. With N > 1 the first run is treated as
+# warmup; mean/median/p95/stddev and p50-p99 latency are written to <stem>_summary.json
+repeat: {{.Repeat}}
+
+# Send a throwaway request before measured runs to load the model (no-op when keep_alive is "0")
+warmup: {{.Warmup}}
+
+# Send every measured run twice and record whether the two responses matched
+# ("deterministic" in JSON results). Set "seed" in inference_configs, or
+# sampling alone will make them differ
+verify_determinism: {{.VerifyDeterminism}}
+
+# Send the first inference config with the streaming health check and record it
+# as that config's first run, instead of a separate non-streaming request
+# (generate endpoint on Ollama backends only)
+stream_benchmark: {{.StreamBenchmark}}
+
+# Skip the per-model streaming health check and run only the inference configs
+# (faster, but no TTFT and the first run includes the model load)
+skip_health_check: {{.SkipHealthCheck}}
+# Run only the streaming health check, one result per model (liveness sweep)
+health_check_only: {{.HealthCheckOnly}}
+
+# Serve live Prometheus metrics at http://<addr>/metrics during the run, e.g. ":9090"
+metrics_addr: {{quote .MetricsAddr}}
+
+# Unload each model after its configs finish (verified via /api/ps) so the next one loads cold
+unload_after: {{.UnloadAfter}}
+
+# Prior JSON Lines result file; (url, model, config) tuples that succeeded there are skipped
+resume: {{quote .Resume}}
+`
+
+// Scaffold renders a commented YAML config populated with the default values.
+func Scaffold() ([]byte, error) {
+	funcs := template.FuncMap{
+		"quote": strconv.Quote,
+		"yaml":  yamlValue,
+	}
+
+	tmpl, err := template.New("scaffold").Funcs(funcs).Parse(scaffoldTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, DefaultConfig()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlValue renders a list or map as YAML suitable for placement after "key:".
+// Empty values are rendered inline (" []" / " {}"), others as an indented block.
+func yamlValue(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	out := strings.TrimRight(string(data), "\n")
+	switch out {
+	case "[]", "{}":
+		return " " + out, nil
+	case "null":
+		return " []", nil
+	}
+
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+	return "\n" + strings.Join(lines, "\n"), nil
+}
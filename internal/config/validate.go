@@ -0,0 +1,403 @@
+/*
+PURPOSE:
+  Semantic validation of a loaded Config.
+  Catches misconfiguration before a long benchmark run starts.
+
+REQUIREMENTS:
+  User-specified:
+  - URLs must be valid http(s) URLs.
+  - Timeouts positive, concurrency >= 1.
+  - inference_configs keys should be recognized Ollama options: a warning by
+    default (with a "did you mean" suggestion), an error under --strict.
+  - verify_determinism warns about inference configs without a seed.
+  - exclude/models must not contradict each other.
+
+  Implementation-discovered:
+  - Report every problem at once (not just the first) with the offending field.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/validate.go
+  - ModeProblems is also called by internal/engine (Run and Plan refuse
+    unknown modes before any work starts)
+
+ERROR HANDLING:
+  - Validate returns a list of Problems; empty means the config is valid.
+  - Warnings returns likely mistakes that don't block a run.
+
+IMPLEMENTATION RULES:
+  - Pure checks only. No network access.
+
+USAGE:
+  problems := cfg.Validate()
+  warnings := cfg.Warnings()
+
+SELF-HEALING INSTRUCTIONS:
+  - If Ollama adds options, extend KnownOptions.
+
+RELATED FILES:
+  - internal/config/config.go
+  - internal/cli/validate.go
+
+MAINTENANCE:
+  - Add checks when adding Config fields with constrained values.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// KnownOptions is the set of inference option keys recognized by Ollama's "options" object.
+var KnownOptions = map[string]bool{
+	"num_keep": true, "seed": true, "num_predict": true, "top_k": true, "top_p": true,
+	"min_p": true, "typical_p": true, "repeat_last_n": true, "temperature": true,
+	"repeat_penalty": true, "presence_penalty": true, "frequency_penalty": true,
+	"mirostat": true, "mirostat_tau": true, "mirostat_eta": true, "penalize_newline": true,
+	"stop": true, "numa": true, "num_ctx": true, "num_batch": true, "num_gpu": true,
+	"main_gpu": true, "low_vram": true, "vocab_only": true, "use_mmap": true,
+	"use_mlock": true, "num_thread": true, "tfs_z": true,
+}
+
+// Problem describes a single semantic issue found in a Config.
+type Problem struct {
+	Field   string
+	Message string
+}
+
+func (p Problem) Error() string {
+	return fmt.Sprintf("%s: %s", p.Field, p.Message)
+}
+
+// Validate runs semantic checks over the config and returns every problem found.
+func (c *Config) Validate() []Problem {
+	var problems []Problem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(c.URLs) == 0 {
+		add("urls", "at least one URL is required")
+	}
+	for i, raw := range c.URLs {
+		if _, err := NormalizeURL(raw); err != nil {
+			add(fmt.Sprintf("urls[%d]", i), "%v", err)
+		}
+	}
+	protocolURLs := make([]string, 0, len(c.URLProtocols))
+	for raw := range c.URLProtocols {
+		protocolURLs = append(protocolURLs, raw)
+	}
+	sort.Strings(protocolURLs)
+	for _, raw := range protocolURLs {
+		if _, err := NormalizeURL(raw); err != nil {
+			add("url_protocols", "%v", err)
+		}
+	}
+
+	if _, err := c.TLSConfig(); err != nil {
+		add("tls", "%v", err)
+	}
+	if _, err := c.ProxyURL(); err != nil {
+		add("proxy", "%v", err)
+	}
+
+	if c.StreamTimeout <= 0 {
+		add("stream_timeout", "must be positive (got %s)", c.StreamTimeout)
+	}
+	if c.LoadTimeout <= 0 {
+		add("load_timeout", "must be positive (got %s)", c.LoadTimeout)
+	}
+	if c.MonitorInterval <= 0 {
+		add("monitor_interval", "must be positive (got %s)", c.MonitorInterval)
+	}
+	if c.RetryDelay < 0 {
+		add("retry_delay", "must not be negative (got %s)", c.RetryDelay)
+	}
+	if c.RetryDeadline < 0 {
+		add("retry_deadline", "must not be negative (got %s)", c.RetryDeadline)
+	}
+	if c.OverallTimeout < 0 {
+		add("overall_timeout", "must not be negative (got %s)", c.OverallTimeout)
+	}
+	if c.DiscoveryTimeout < 0 {
+		add("discovery_timeout", "must not be negative (got %s)", c.DiscoveryTimeout)
+	}
+	for pattern, d := range c.ModelTimeouts {
+		if pattern == "" {
+			add("model_timeouts", "keys must not be empty")
+		} else if d <= 0 {
+			add(fmt.Sprintf("model_timeouts[%q]", pattern), "must be positive (got %s)", d)
+		}
+	}
+	if c.InterRunDelay < 0 {
+		add("inter_run_delay", "must not be negative (got %s)", c.InterRunDelay)
+	}
+	if c.MaxRetries < 1 {
+		add("max_retries", "must be at least 1 (got %d)", c.MaxRetries)
+	}
+	if c.Concurrency < 1 {
+		add("concurrency", "must be at least 1 (got %d)", c.Concurrency)
+	}
+	if c.ModelConcurrency < 1 {
+		add("model_concurrency", "must be at least 1 (got %d)", c.ModelConcurrency)
+	}
+	for u, n := range c.URLModelConcurrency {
+		if n < 1 {
+			add("url_model_concurrency", "%s must be at least 1 (got %d)", u, n)
+		}
+	}
+	if c.GlobalRateLimit < 0 {
+		add("global_rate_limit", "must not be negative (got %d)", c.GlobalRateLimit)
+	}
+	if c.Repeat < 1 {
+		add("repeat", "must be at least 1 (got %d)", c.Repeat)
+	}
+
+	problems = append(problems, c.ModeProblems()...)
+	if c.MaxModels < 0 {
+		add("max_models", "must not be negative (got %d)", c.MaxModels)
+	}
+
+	if len(c.Outputs) == 0 {
+		add("outputs", "at least one output format is required")
+	}
+
+	if _, err := c.CSVComma(); err != nil {
+		add("csv_delimiter", "must be a single character other than a quote or newline (got %q)", c.CSVDelimiter)
+	}
+	for i, size := range c.CtxSweep {
+		if size <= 0 {
+			add(fmt.Sprintf("ctx_sweep[%d]", i), "must be positive (got %d)", size)
+		}
+	}
+	if c.CostPerGPUHour < 0 {
+		add("cost_per_gpu_hour", "must not be negative (got %g)", c.CostPerGPUHour)
+	}
+	if c.GPUCount < 0 {
+		add("gpu_count", "must not be negative (got %d)", c.GPUCount)
+	}
+	if c.MaxVRAMBytes < 0 {
+		add("max_vram_bytes", "must not be negative (got %d)", c.MaxVRAMBytes)
+	}
+	if c.MaxTokens < 0 {
+		add("max_tokens", "must not be negative (got %d)", c.MaxTokens)
+	}
+	if c.ResponseMaxChars < 0 {
+		add("response_max_chars", "must not be negative (got %d)", c.ResponseMaxChars)
+	}
+	if c.CSVPrecision < 0 {
+		add("csv_precision", "must not be negative (got %d)", c.CSVPrecision)
+	}
+
+	for i, p := range c.IncludeRegex {
+		if _, err := regexp.Compile(p); err != nil {
+			add(fmt.Sprintf("include_regex[%d]", i), "invalid pattern %q: %v", p, err)
+		}
+	}
+	for i, p := range c.ExcludeRegex {
+		if _, err := regexp.Compile(p); err != nil {
+			add(fmt.Sprintf("exclude_regex[%d]", i), "invalid pattern %q: %v", p, err)
+		}
+	}
+
+	for _, k := range []string{"Model", "URL"} {
+		if _, ok := c.Vars[k]; ok {
+			add("vars", "%q is a built-in prompt variable and cannot be overridden", k)
+		}
+	}
+
+	for i, path := range c.Images {
+		if _, err := os.Stat(path); err != nil {
+			add(fmt.Sprintf("images[%d]", i), "%v", err)
+		}
+	}
+
+	for k := range c.Labels {
+		if strings.TrimSpace(k) == "" {
+			add("labels", "label keys must not be empty")
+		}
+	}
+
+	for _, m := range c.Models {
+		for _, ex := range c.Exclude {
+			if strings.Contains(strings.ToLower(m), strings.ToLower(ex)) {
+				add("models", "%q is listed explicitly but excluded by filter %q", m, ex)
+			}
+		}
+	}
+
+	return problems
+}
+
+// ModeProblems checks the values that select how a run behaves: endpoint,
+// protocol, fail_fast, model_order, output formats, response format and the
+// health check modes. Run and Plan refuse a config with any of them; the
+// range checks in Validate are left to the engine's clamps.
+func (c *Config) ModeProblems() []Problem {
+	var problems []Problem
+	add := func(field, format string, args ...interface{}) {
+		problems = append(problems, Problem{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch c.Endpoint {
+	case EndpointGenerate, EndpointChat, EndpointEmbeddings:
+	default:
+		add("endpoint", "unknown endpoint %q (expected %s, %s or %s)", c.Endpoint, EndpointGenerate, EndpointChat, EndpointEmbeddings)
+	}
+	for _, u := range c.URLs {
+		switch p := c.ProtocolFor(u); p {
+		case ProtocolOllama, ProtocolOpenAI:
+		default:
+			add("protocol", "unknown protocol %q for %s (expected %s or %s)", p, u, ProtocolOllama, ProtocolOpenAI)
+		}
+	}
+
+	switch c.FailFast {
+	case FailFastConfig, FailFastModel, FailFastNone:
+	default:
+		add("fail_fast", "unknown level %q (expected %s, %s or %s)", c.FailFast, FailFastConfig, FailFastModel, FailFastNone)
+	}
+
+	switch c.ModelOrder {
+	case ModelOrderName, ModelOrderSize, ModelOrderDiscovery:
+	default:
+		add("model_order", "unknown order %q (expected %s, %s or %s)", c.ModelOrder, ModelOrderName, ModelOrderSize, ModelOrderDiscovery)
+	}
+
+	for i, o := range c.Outputs {
+		switch strings.ToLower(o) {
+		case OutputCSV, OutputJSON, OutputJSONArray, OutputSQLite, OutputMarkdown, OutputHTML, OutputInflux:
+		default:
+			add(fmt.Sprintf("outputs[%d]", i), "unknown output format %q (expected %s, %s, %s, %s, %s, %s or %s)", o, OutputCSV, OutputJSON, OutputJSONArray, OutputSQLite, OutputMarkdown, OutputHTML, OutputInflux)
+		}
+	}
+
+	if c.SkipHealthCheck && c.HealthCheckOnly {
+		add("health_check_only", "conflicts with skip_health_check; set at most one")
+	}
+
+	switch f := c.Format.(type) {
+	case nil, map[string]interface{}:
+	case string:
+		if f != "" && f != "json" {
+			add("format", "must be \"json\" or a JSON schema object, got %q", f)
+		}
+	default:
+		add("format", "must be \"json\" or a JSON schema object, got %T", f)
+	}
+	return problems
+}
+
+// Warnings returns issues that don't stop a run but probably aren't what the
+// user meant. They become errors under --strict.
+func (c *Config) Warnings() []Problem {
+	var warnings []Problem
+	// Ollama silently ignores unknown options, so a typo like num_cxt would
+	// otherwise benchmark the default setting without any hint
+	for i, opts := range c.InferConfigs {
+		keys := make([]string, 0, len(opts))
+		for k := range opts {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if KnownOptions[k] {
+				continue
+			}
+			msg := fmt.Sprintf("unknown Ollama option %q (Ollama ignores it)", k)
+			if guess := closestOption(k); guess != "" {
+				msg += fmt.Sprintf("; did you mean %q?", guess)
+			}
+			warnings = append(warnings, Problem{Field: fmt.Sprintf("inference_configs[%d]", i), Message: msg})
+		}
+		if _, ok := opts["num_ctx"]; ok && len(c.CtxSweep) > 0 {
+			warnings = append(warnings, Problem{
+				Field:   fmt.Sprintf("inference_configs[%d]", i),
+				Message: "num_ctx is replaced by each ctx_sweep size",
+			})
+		}
+		if _, ok := opts["seed"]; c.VerifyDeterminism && !ok {
+			warnings = append(warnings, Problem{
+				Field:   fmt.Sprintf("inference_configs[%d]", i),
+				Message: "verify_determinism without a seed; sampling will make responses differ",
+			})
+		}
+	}
+	if len(c.CtxSweep) > 0 && c.Endpoint == EndpointEmbeddings {
+		warnings = append(warnings, Problem{Field: "ctx_sweep", Message: "ignored with the embeddings endpoint"})
+	}
+	if c.SkipHealthCheck && c.StreamBenchmark {
+		warnings = append(warnings, Problem{Field: "stream_benchmark", Message: "has no effect with skip_health_check"})
+	}
+	if c.SkipHealthCheck && c.TokenTimings {
+		warnings = append(warnings, Problem{Field: "token_timings", Message: "records nothing with skip_health_check (only the stream is timed)"})
+	}
+	if c.HealthCheckOnly && c.Endpoint == EndpointEmbeddings {
+		warnings = append(warnings, Problem{Field: "health_check_only", Message: "ignored with the embeddings endpoint (embeddings have no stream test)"})
+	}
+	if len(c.Images) > 0 {
+		openAI := false
+		for _, u := range c.URLs {
+			openAI = openAI || c.ProtocolFor(u) == ProtocolOpenAI
+		}
+		if c.Endpoint != EndpointGenerate || openAI {
+			warnings = append(warnings, Problem{Field: "images", Message: "only sent with the generate endpoint on Ollama backends; other requests go without them"})
+		}
+	}
+	if c.StructuredOutput() {
+		openAI := false
+		for _, u := range c.URLs {
+			openAI = openAI || c.ProtocolFor(u) == ProtocolOpenAI
+		}
+		if c.Endpoint == EndpointEmbeddings || openAI {
+			warnings = append(warnings, Problem{Field: "format", Message: "only sent to Ollama generate and chat requests; other requests go without it"})
+		}
+	}
+	if !c.TemplatePrompts() && (strings.Contains(c.Prompt, "{{.Model}}") || strings.Contains(c.Prompt, "{{.URL}}")) {
+		warnings = append(warnings, Problem{Field: "prompt", Message: "uses {{.Model}}/{{.URL}} but prompt_templates is off, so it is sent as written"})
+	}
+	if c.ImageSweep && len(c.Images) == 0 {
+		warnings = append(warnings, Problem{Field: "image_sweep", Message: "has no effect without images"})
+	}
+	if c.MaxVRAMBytes > 0 && c.MaxVRAMBytes < 1<<30 {
+		warnings = append(warnings, Problem{Field: "max_vram_bytes", Message: fmt.Sprintf("%d is under 1 GiB; the value is in bytes (24 GiB = 25769803776)", c.MaxVRAMBytes)})
+	}
+	return warnings
+}
+
+// closestOption suggests the known option within two edits of key, if any.
+func closestOption(key string) string {
+	best, bestDist := "", 3
+	for opt := range KnownOptions {
+		if d := editDistance(key, opt); d < bestDist || (d == bestDist && opt < best) {
+			best, bestDist = opt, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -11,7 +11,30 @@ REQUIREMENTS:
 
   Implementation-discovered:
   - Needs http.Client with timeouts.
+  - Timeouts are reported by kind (connect, header/load, overall) so the
+    result's error column says which budget to raise.
+  - model_timeouts gives each attempt a per-model deadline (requestBudget);
+    the client-wide timeouts are raised to the longest one so they never cut
+    a larger model's budget short.
+  - Listing requests (/api/tags, /v1/models, /api/ps) get their own short
+    discovery_timeout: the client-wide timeout covers a model load, so a
+    host that hangs would otherwise stall discovery for minutes.
   - Resilience against "garbage" JSON (invalid chunks).
+  - Every request is built by newRequest, so URL joining, the JSON content
+    type, User-Agent and custom headers can't drift between call sites.
+  - The streamed text is assembled rather than discarded; when the final chunk
+    lacks eval_count/eval_duration, tokens/sec comes from counting chunks.
+  - retry_deadline bounds the retry loop by wall clock: no new attempt starts
+    once it has passed, so the worst case per request is retry_deadline plus
+    one request budget, however long each failed attempt hung.
+  - global_rate_limit: one limiter shared by all URL workers. Each request waits
+    before its clock starts; a retry waits retry_delay and then queues again.
+  - token_timings: processStream keeps per-token arrival gaps only when the
+    token log is set, and writes them once the stream completes, so failed
+    attempts leave no partial timings.
+  - Older Ollama releases have no /api/ps. The first 404 marks the URL (noPS),
+    logs once, and from then on runningModels returns errNoPS without a
+    request, so the monitor, VRAM columns and unload checks go quiet.
 
 ARCHITECTURE INTEGRATION:
   - Called by: internal/cli
@@ -26,11 +49,13 @@ IMPLEMENTATION RULES:
   - Use net/http.
   - Enforce timeouts.
   - Parse streaming JSON line-by-line.
+  - Every method that talks to a backend takes ctx first, so an interrupted
+    run also cancels discovery and /api/ps polling.
 
 USAGE:
   e := engine.New(cfg)
-  models, err := e.GetModels(url)
-  err := e.StreamInference(url, model, prompt)
+  models, err := e.GetModels(ctx, url)
+  ttft, err := e.StreamInference(ctx, url, model, prompt, nil, nil, &res) // images, options and res may be nil
 
 SELF-HEALING INSTRUCTIONS:
   - If Ollama API changes, update endpoints (/api/tags, /api/generate).
@@ -50,22 +75,34 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptrace"
+	"os"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/daryltucker/forest-runner/internal/config"
 	"github.com/daryltucker/forest-runner/internal/model"
 	"github.com/daryltucker/forest-runner/internal/output"
+	"golang.org/x/time/rate"
 )
 
 // Engine handles Ollama interactions.
 type Engine struct {
 	Config *config.Config
 	Client *http.Client
+
+	limiter  *rate.Limiter    // global_rate_limit; nil when unlimited
+	tokenLog *output.TokenLog // token_timings sidecar; nil when off
+
+	psMu sync.Mutex
+	noPS map[string]bool // URLs whose /api/ps answered 404
 }
 
 // New creates a new Engine.
@@ -76,21 +113,261 @@ func New(cfg *config.Config) *Engine {
 
 	// ResponseHeaderTimeout covers the time until we receive the first response byte
 	// (Step 3: Headers). This is where model loading happens.
-	transport.ResponseHeaderTimeout = cfg.LoadTimeout
+	// A model_timeouts entry longer than load_timeout lifts it; the per-request
+	// deadline still enforces each model's own budget.
+	transport.ResponseHeaderTimeout = max(cfg.LoadTimeout, cfg.LongestRequestBudget())
+
+	// Private CA, skipped verification and mTLS; loadConfig and Validate have
+	// already reported a broken TLS setup, so an error here keeps the defaults
+	if tlsCfg, err := cfg.TLSConfig(); err == nil && tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
 
-	return &Engine{
+	// A configured proxy replaces the environment's (the clone keeps ProxyFromEnvironment)
+	if proxy, err := cfg.ProxyURL(); err == nil && proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	// unix:///path/to/ollama.sock URLs dial the socket; http(s) is unaffected
+	transport.RegisterProtocol("unix", newUnixTransport(transport))
+
+	// overall_timeout, when set, is a hard ceiling that replaces the computed budget
+	overall := cfg.LoadTimeout + (cfg.StreamTimeout * 2)
+	if cfg.OverallTimeout > 0 {
+		overall = cfg.OverallTimeout
+	}
+	overall = max(overall, cfg.LongestRequestBudget())
+
+	e := &Engine{
 		Config: cfg,
 		Client: &http.Client{
 			Transport: transport,
 			// The overall timeout must cover Loading + Generation
-			Timeout: cfg.LoadTimeout + (cfg.StreamTimeout * 2),
+			Timeout: overall,
 		},
 	}
+	// One shared limiter for every URL worker; burst 1 spaces requests evenly
+	if cfg.GlobalRateLimit > 0 {
+		e.limiter = rate.NewLimiter(rate.Limit(cfg.GlobalRateLimit), 1)
+	}
+	return e
+}
+
+// waitTurn blocks until the global rate limiter admits another inference request.
+func (e *Engine) waitTurn(ctx context.Context) error {
+	if e.limiter == nil {
+		return nil
+	}
+	return e.limiter.Wait(ctx)
+}
+
+// retryWait pauses before a retry: retry_delay first, then the rate limiter,
+// so retries count against global_rate_limit like any other request.
+func (e *Engine) retryWait(ctx context.Context) error {
+	if err := sleepCtx(ctx, e.Config.RetryDelay); err != nil {
+		return err
+	}
+	return e.waitTurn(ctx)
+}
+
+// retryAllowed reports whether another attempt may start under retry_deadline,
+// measured from the first attempt: the deadline must leave room for retry_delay.
+// max_retries still caps the attempt count either way.
+func (e *Engine) retryAllowed(retryStart time.Time, modelName string, lastErr error) bool {
+	if e.Config.RetryDeadline <= 0 || time.Since(retryStart)+e.Config.RetryDelay < e.Config.RetryDeadline {
+		return true
+	}
+	output.Logger.Warn("Not retrying: retry_deadline exceeded", "model", modelName, "retry_deadline", e.Config.RetryDeadline, "error", lastErr)
+	return false
+}
+
+// sleepCtx waits for d or until ctx is cancelled, returning ctx.Err() in the latter case.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// requestBudget is the deadline for one inference attempt against modelName:
+// its model_timeouts entry, else overall_timeout if set, else
+// load_timeout + stream_timeout.
+func (e *Engine) requestBudget(modelName string) time.Duration {
+	return e.Config.RequestBudgetFor(modelName)
+}
+
+// classifyRequestError names which timeout fired so the result's error column
+// says which budget to tune: connect (dial), header (model load, load_timeout)
+// or overall (request deadline). Anything else is a plain connection error.
+func (e *Engine) classifyRequestError(err error, server, modelName string) error {
+	var opErr *net.OpError
+	msg := err.Error()
+	switch {
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return withKind(ErrorKindTimeout, 0, fmt.Errorf("Connect Timeout: %w", err))
+	case strings.Contains(msg, "timeout awaiting response headers"):
+		return withKind(ErrorKindTimeout, 0, fmt.Errorf("%s Header Timeout (model loading? load_timeout=%s): %w", server, e.Config.LoadTimeout, err))
+	case errors.Is(err, context.DeadlineExceeded) || strings.Contains(msg, "Client.Timeout exceeded"):
+		return withKind(ErrorKindTimeout, 0, fmt.Errorf("Overall Timeout (request exceeded %s): %w", e.requestBudget(modelName), err))
+	}
+	return withKind(ErrorKindNetwork, 0, fmt.Errorf("Network/Connection Error: %w", err))
+}
+
+// errAPI marks errors the server reports in the response body ({"error": "..."}).
+// They are deterministic (e.g. "model not found"), so they are never retried.
+var errAPI = errors.New("Ollama API Error")
+
+// isRetryable reports whether a failed attempt is worth repeating. Network
+// errors, timeouts and 5xx responses are transient; other 4xx responses and
+// in-band API errors fail the same way every time.
+func isRetryable(err error, status int) bool {
+	if err == nil || errors.Is(err, errAPI) {
+		return false
+	}
+	if kind, _ := errorDetails(err); kind == ErrorKindContext {
+		return false // The same prompt won't fit on a second try
+	}
+	if status == http.StatusRequestTimeout || status == http.StatusTooManyRequests {
+		return true
+	}
+	return status < 400 || status >= 500
+}
+
+// DefaultUserAgent identifies forest-runner traffic in backend logs.
+// The CLI sets it to forest-runner/<version>; user_agent overrides it.
+var DefaultUserAgent = "forest-runner/dev"
+
+// newRequest builds every request sent to a backend: baseURL + path, a JSON
+// body when one is given, the User-Agent and the configured custom headers
+// (e.g. Authorization). Header values are expanded against the environment so
+// secrets such as "Bearer ${OLLAMA_TOKEN}" never need to be written to the config file.
+func (e *Engine) newRequest(ctx context.Context, method, baseURL, path string, body []byte) (*http.Request, error) {
+	url := strings.TrimRight(baseURL, "/") + path
+
+	// bytes.Reader lets net/http set GetBody, so redirects/retries can rewind the body
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	userAgent := DefaultUserAgent
+	if e.Config.UserAgent != "" {
+		userAgent = e.Config.UserAgent
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range e.Config.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	return req, nil
+}
+
+// get performs a GET request for path on baseURL.
+func (e *Engine) get(ctx context.Context, baseURL, path string) (*http.Response, error) {
+	req, err := e.newRequest(ctx, "GET", baseURL, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return e.Client.Do(req)
 }
 
 // GetModels returns a list of available models from an Ollama host.
-func (e *Engine) GetModels(baseURL string) ([]string, error) {
-	resp, err := e.Client.Get(fmt.Sprintf("%s/api/tags", baseURL))
+// OpenAI-compatible backends are queried via /v1/models instead.
+func (e *Engine) GetModels(ctx context.Context, baseURL string) ([]string, error) {
+	models, err := e.GetModelsDetailed(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, m := range models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
+
+// GetModelSizes returns the on-disk size in bytes of every model on an Ollama host.
+func (e *Engine) GetModelSizes(ctx context.Context, baseURL string) (map[string]int64, error) {
+	models, err := e.GetModelsDetailed(ctx, baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[string]int64, len(models))
+	for _, m := range models {
+		sizes[m.Name] = m.Size
+	}
+	return sizes, nil
+}
+
+// GetModelsDetailed returns every installed model with its size and modification
+// time, in the order the server lists them. OpenAI-compatible backends only
+// report names.
+func (e *Engine) GetModelsDetailed(ctx context.Context, baseURL string) ([]model.ModelInfo, error) {
+	ctx, cancel := e.discoveryContext(ctx)
+	defer cancel()
+
+	if e.Config.ProtocolFor(baseURL) == config.ProtocolOpenAI {
+		names, err := e.getOpenAIModels(ctx, baseURL)
+		if err != nil {
+			return nil, e.discoveryErr(ctx, err)
+		}
+		models := make([]model.ModelInfo, len(names))
+		for i, name := range names {
+			models[i] = model.ModelInfo{Name: name}
+		}
+		return models, nil
+	}
+
+	resp, err := e.get(ctx, baseURL, "/api/tags")
+	if err != nil {
+		return nil, e.discoveryErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var payload struct {
+		Models []model.ModelInfo `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, e.discoveryErr(ctx, err)
+	}
+	return payload.Models, nil
+}
+
+// discoveryContext bounds a listing request (/api/tags, /v1/models, /api/ps)
+// by discovery_timeout rather than the much longer inference budget.
+func (e *Engine) discoveryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.Config.DiscoveryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.Config.DiscoveryTimeout)
+}
+
+// discoveryErr names discovery_timeout when it is what cut a listing request short.
+func (e *Engine) discoveryErr(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return withKind(ErrorKindTimeout, 0, fmt.Errorf("Discovery Timeout (no answer within discovery_timeout=%s): %w", e.Config.DiscoveryTimeout, err))
+	}
+	return err
+}
+
+// getOpenAIModels lists model IDs from an OpenAI-compatible /v1/models endpoint.
+func (e *Engine) getOpenAIModels(ctx context.Context, baseURL string) ([]string, error) {
+	resp, err := e.get(ctx, baseURL, "/v1/models")
 	if err != nil {
 		return nil, err
 	}
@@ -101,9 +378,9 @@ func (e *Engine) GetModels(baseURL string) ([]string, error) {
 	}
 
 	var payload struct {
-		Models []struct {
-			Name string `json:"name"`
-		} `json:"models"`
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
@@ -111,50 +388,189 @@ func (e *Engine) GetModels(baseURL string) ([]string, error) {
 	}
 
 	var names []string
-	for _, m := range payload.Models {
-		names = append(names, m.Name)
+	for _, m := range payload.Data {
+		names = append(names, m.ID)
 	}
 	return names, nil
 }
 
-// GetRunningModelInfo retrieves memory stats for a running model from /api/ps.
-func (e *Engine) GetRunningModelInfo(baseURL, modelName string) (int64, int64, error) {
-	resp, err := e.Client.Get(fmt.Sprintf("%s/api/ps", baseURL))
+// runningModel is one entry of /api/ps.
+type runningModel struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	SizeVRAM int64  `json:"size_vram"`
+}
+
+// errNoPS reports a host without /api/ps (Ollama releases that predate it).
+var errNoPS = errors.New("/api/ps is not supported on this host")
+
+// psSupported reports whether baseURL's /api/ps is worth polling: false once
+// it has answered 404.
+func (e *Engine) psSupported(baseURL string) bool {
+	e.psMu.Lock()
+	defer e.psMu.Unlock()
+	return !e.noPS[baseURL]
+}
+
+// markNoPS records that baseURL has no /api/ps, logging it the first time.
+func (e *Engine) markNoPS(baseURL string) {
+	e.psMu.Lock()
+	defer e.psMu.Unlock()
+	if e.noPS[baseURL] {
+		return
+	}
+	if e.noPS == nil {
+		e.noPS = make(map[string]bool)
+	}
+	e.noPS[baseURL] = true
+	output.Logger.Warn("ps unsupported on this host; VRAM stats disabled (placement guards and headroom checks are off)", "url", baseURL)
+}
+
+// runningModels lists the models loaded on baseURL, from /api/ps. It returns
+// errNoPS without a request once the host has answered 404.
+func (e *Engine) runningModels(ctx context.Context, baseURL string) ([]runningModel, error) {
+	if !e.psSupported(baseURL) {
+		return nil, errNoPS
+	}
+	ctx, cancel := e.discoveryContext(ctx)
+	defer cancel()
+	resp, err := e.get(ctx, baseURL, "/api/ps")
 	if err != nil {
-		return 0, 0, err
+		return nil, e.discoveryErr(ctx, err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		e.markNoPS(baseURL)
+		return nil, errNoPS
+	}
 	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("bad status: %s", resp.Status)
+		return nil, fmt.Errorf("bad status: %s", resp.Status)
 	}
 
 	var payload struct {
-		Models []struct {
-			Name     string `json:"name"`
-			Size     int64  `json:"size"`
-			SizeVRAM int64  `json:"size_vram"`
-		} `json:"models"`
+		Models []runningModel `json:"models"`
 	}
-
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload.Models, nil
+}
+
+// showInfo is the part of an /api/show reply the engine reads.
+type showInfo struct {
+	ModelInfo     map[string]interface{} `json:"model_info"`
+	ProjectorInfo map[string]interface{} `json:"projector_info"` // Vision projector (older multimodal releases)
+	Capabilities  []string               `json:"capabilities"`   // e.g. "completion", "vision"
+	Details       struct {
+		Family   string   `json:"family"`
+		Families []string `json:"families"`
+	} `json:"details"`
+}
+
+// showModel fetches a model's details from /api/show.
+func (e *Engine) showModel(ctx context.Context, baseURL, modelName string) (showInfo, error) {
+	var info showInfo
+	body, err := json.Marshal(map[string]string{"model": modelName})
+	if err != nil {
+		return info, err
+	}
+	req, err := e.newRequest(ctx, "POST", baseURL, "/api/show", body)
+	if err != nil {
+		return info, err
+	}
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return info, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
+
+// GetRunningModelInfo retrieves memory stats for a running model from /api/ps.
+func (e *Engine) GetRunningModelInfo(ctx context.Context, baseURL, modelName string) (int64, int64, error) {
+	models, err := e.runningModels(ctx, baseURL)
+	if err != nil {
 		return 0, 0, err
 	}
 
-	for _, m := range payload.Models {
-		// Loosely match model name or exact match
-		if m.Name == modelName || strings.HasPrefix(m.Name, modelName) {
+	// Exact match first ("llama3" and "llama3:latest" are the same model)
+	want := withTag(modelName)
+	for _, m := range models {
+		if withTag(m.Name) == want {
 			return m.Size, m.SizeVRAM, nil
 		}
 	}
 
-	return 0, 0, nil // Not found (might have unloaded?)
+	// Prefix match only when unambiguous: "llama3" must not pick up "llama3.1:70b"
+	// when both are loaded, or the VRAM columns would describe the wrong model.
+	var candidates []string
+	var size, sizeVRAM int64
+	for _, m := range models {
+		if strings.HasPrefix(m.Name, modelName) {
+			candidates = append(candidates, m.Name)
+			size, sizeVRAM = m.Size, m.SizeVRAM
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return 0, 0, nil // Not found (might have unloaded?)
+	case 1:
+		return size, sizeVRAM, nil
+	default:
+		return 0, 0, fmt.Errorf("ambiguous model name %q matches running models %s", modelName, strings.Join(candidates, ", "))
+	}
+}
+
+// withTag returns the model name with Ollama's implicit ":latest" tag added if it has none.
+func withTag(name string) string {
+	if strings.Contains(name, ":") {
+		return name
+	}
+	return name + ":latest"
+}
+
+// startMonitor launches monitorLoading for a single request attempt. The returned
+// stop function cancels the monitor and blocks until its goroutine has exited, so
+// no /api/ps poller outlives the attempt that started it.
+func (e *Engine) startMonitor(ctx context.Context, baseURL, modelName string, cancelRequest context.CancelFunc) (<-chan error, func()) {
+	// Both guards permissive: any placement is acceptable, so polling is pointless.
+	// A nil channel never delivers an abort.
+	if !e.Config.GPUOnly && e.Config.CPUOnlyAllowed {
+		return nil, func() {}
+	}
+	// Nothing to poll on a host without /api/ps
+	if !e.psSupported(baseURL) {
+		return nil, func() {}
+	}
+
+	monitorCtx, cancelMonitor := context.WithCancel(ctx)
+	abort := make(chan error, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		e.monitorLoading(monitorCtx, baseURL, modelName, abort, cancelRequest)
+	}()
+
+	return abort, func() {
+		cancelMonitor()
+		<-done
+	}
 }
 
 // monitorLoading polls /api/ps during the loading phase to ensure model placement
 // adheres to the configured GPU/CPU guards.
 func (e *Engine) monitorLoading(ctx context.Context, baseURL, modelName string, abort chan<- error, cancel context.CancelFunc) {
-	ticker := time.NewTicker(2 * time.Second)
+	interval := e.Config.MonitorInterval
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -162,7 +578,10 @@ func (e *Engine) monitorLoading(ctx context.Context, baseURL, modelName string,
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			size, sizeVRAM, err := e.GetRunningModelInfo(baseURL, modelName)
+			size, sizeVRAM, err := e.GetRunningModelInfo(ctx, baseURL, modelName)
+			if errors.Is(err, errNoPS) {
+				return
+			}
 			if err != nil {
 				// Don't fail the monitor just because ps failed once (race condition during load)
 				continue
@@ -176,7 +595,7 @@ func (e *Engine) monitorLoading(ctx context.Context, baseURL, modelName string,
 			// 100% CPU Check
 			if sizeVRAM == 0 && !e.Config.CPUOnlyAllowed {
 				select {
-				case abort <- fmt.Errorf("ABORT: Model loaded 100%% on CPU (cpu_only_allowed=false)"):
+				case abort <- withKind(ErrorKindPlacement, 0, fmt.Errorf("ABORT: Model loaded 100%% on CPU (cpu_only_allowed=false)")):
 					cancel()
 				default:
 				}
@@ -186,7 +605,7 @@ func (e *Engine) monitorLoading(ctx context.Context, baseURL, modelName string,
 			// Split Load Check (any part on CPU)
 			if sizeVRAM < size && e.Config.GPUOnly {
 				select {
-				case abort <- fmt.Errorf("ABORT: Model is partially on CPU (gpu_only=true)"):
+				case abort <- withKind(ErrorKindPlacement, 0, fmt.Errorf("ABORT: Model is partially on CPU (gpu_only=true)")):
 					cancel()
 				default:
 				}
@@ -197,15 +616,29 @@ func (e *Engine) monitorLoading(ctx context.Context, baseURL, modelName string,
 }
 
 // StreamInference runs a streaming inference request.
-func (e *Engine) StreamInference(baseURL, modelName, prompt string) error {
-	reqBody, _ := json.Marshal(map[string]interface{}{
+// It returns the time-to-first-token, measured from the first response byte
+// (i.e. after the model has loaded) to the first non-empty generated token.
+// Images and options are sent only when given, so the plain health check runs
+// with the model's defaults. If res is non-nil it receives the assembled response and
+// the stream's metrics.
+func (e *Engine) StreamInference(ctx context.Context, baseURL, modelName, prompt string, images []string, options map[string]interface{}, res *model.Result) (time.Duration, error) {
+	payload := map[string]interface{}{
 		"model":      modelName,
 		"prompt":     prompt,
 		"stream":     true,
 		"keep_alive": e.Config.KeepAlive,
-	})
+	}
+	if len(images) > 0 {
+		payload["images"] = images
+	}
+	e.setFormat(payload)
+	if len(options) > 0 {
+		payload["options"] = options
+	}
+	reqBody, _ := json.Marshal(payload)
 
 	// Setup Trace
+	var firstByte time.Time
 	trace := &httptrace.ClientTrace{
 		GotConn: func(connInfo httptrace.GotConnInfo) {
 			output.Logger.Info("Network: Connected", "remote", connInfo.Conn.RemoteAddr(), "reused", connInfo.Reused)
@@ -214,81 +647,131 @@ func (e *Engine) StreamInference(baseURL, modelName, prompt string) error {
 			output.Logger.Info("Network: Request Sent. Waiting for model to load...", "model", modelName)
 		},
 		GotFirstResponseByte: func() {
+			firstByte = time.Now()
 			output.Logger.Info("Network: First Byte Received", "model", modelName)
 		},
 	}
 
-	// The context timeout must cover both the Load phase and the Generation phase.
-	ctx, cancel := context.WithCancel(context.Background())
-	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, e.Config.LoadTimeout+e.Config.StreamTimeout)
-	defer cancel()
-	defer timeoutCancel()
-
-	ctx = timeoutCtx // Use the timeout-wrapped context
-
-	// Launch Loading Monitor
-	abort := make(chan error, 1)
-	go e.monitorLoading(ctx, baseURL, modelName, abort, cancel)
-
-	ctx = httptrace.WithClientTrace(ctx, trace)
-
-	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/generate", baseURL), bytes.NewBuffer(reqBody))
-	if err != nil {
-		return err
+	if err := e.waitTurn(ctx); err != nil {
+		return 0, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
 	// Retry loop
+	// Each attempt gets a fresh request, timeout and loading monitor: a request
+	// whose context died on the previous attempt can't be reused.
 	var lastErr error
+	retryStart := time.Now()
 	for i := 0; i < e.Config.MaxRetries; i++ {
-		// Check for specific abort error before retrying
-		select {
-		case err := <-abort:
-			return err
-		default:
-		}
-
 		if i > 0 {
-			time.Sleep(e.Config.RetryDelay)
-			output.Logger.Info("Retrying streaming...", "attempt", i+1)
+			if !e.retryAllowed(retryStart, modelName, lastErr) {
+				break
+			}
+			if err := e.retryWait(ctx); err != nil {
+				return 0, err
+			}
+			output.Logger.Info("Retrying streaming...", "attempt", i+1, "error", lastErr)
 		}
 
-		// Re-create request body reader for retry
-		req.Body = io.NopCloser(bytes.NewBuffer(reqBody))
+		var status int
+		start := time.Now()
+		stream := model.Result{Model: modelName, URL: baseURL, Config: options, Timestamp: start}
+		ttft, abortErr, loopErr := func() (time.Duration, error, error) {
+			// The context timeout must cover both the Load phase and the Generation phase.
+			attemptCtx, cancel := context.WithCancel(ctx)
+			timeoutCtx, timeoutCancel := context.WithTimeout(attemptCtx, e.requestBudget(modelName))
+			defer timeoutCancel()
+			defer cancel()
+
+			// Launch Loading Monitor (stopped before this attempt returns)
+			abort, stopMonitor := e.startMonitor(timeoutCtx, baseURL, modelName, cancel)
+			defer stopMonitor()
 
-		resp, err := e.Client.Do(req)
-		if err != nil {
-			// Check for specific abort error before classifying as network error
+			firstByte = time.Time{}
+			req, err := e.newRequest(httptrace.WithClientTrace(timeoutCtx, trace), "POST", baseURL, "/api/generate", reqBody)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			resp, err := e.Client.Do(req)
+			if err != nil {
+				// Check for specific abort error before classifying as network error
+				select {
+				case abortErr := <-abort:
+					return 0, abortErr, nil
+				default:
+				}
+
+				return 0, nil, e.classifyRequestError(err, "Ollama", modelName)
+			}
+			defer resp.Body.Close()
+
+			status = resp.StatusCode
+			if resp.StatusCode != http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				return 0, nil, statusError("Ollama", resp, body)
+			}
+
+			// Process Stream
+			if firstByte.IsZero() {
+				firstByte = time.Now()
+			}
+			text, done, err := e.processStream(resp.Body, firstByte, &stream)
+
+			// A placement guard may have cut the stream short
 			select {
 			case abortErr := <-abort:
-				return abortErr
+				return 0, abortErr, nil
 			default:
 			}
 
-			if strings.Contains(err.Error(), "awaiting headers") {
-				lastErr = fmt.Errorf("Ollama Header Timeout (model loading?): %w", err)
-			} else {
-				lastErr = fmt.Errorf("Network/Connection Error: %w", err)
+			if err != nil {
+				return 0, nil, fmt.Errorf("stream interrupted: %w", e.classifyRequestError(err, "Ollama", modelName))
 			}
-			continue
-		}
+			if !done {
+				return 0, nil, withKind(ErrorKindAPI, 0, fmt.Errorf("stream incomplete or failed to start"))
+			}
+			stream.Response = text
+			return stream.TimeToFirstToken, nil, nil
+		}()
 
-		// Process Stream
-		success := e.processStream(resp.Body)
-		resp.Body.Close()
+		if abortErr != nil {
+			return 0, abortErr
+		}
+		if loopErr == nil {
+			if res != nil {
+				stream.Duration = time.Since(start)
+				stream.ResponseRunes = utf8.RuneCountInString(stream.Response)
+				*res = stream
+			}
+			return ttft, nil
+		}
+		lastErr = loopErr
 
-		if success {
-			return nil
+		// Interrupted by the caller (e.g. SIGINT): don't retry
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if !isRetryable(loopErr, status) {
+			output.Logger.Warn("Not retrying: error is not transient", "model", modelName, "error", loopErr)
+			break
 		}
-		lastErr = fmt.Errorf("stream incomplete or failed to start")
 	}
 
-	return lastErr
+	return 0, lastErr
 }
 
-func (e *Engine) processStream(body io.Reader) bool {
+// processStream consumes a streaming /api/generate body and returns the
+// assembled response text and whether the terminal chunk was received.
+// res receives the delay between firstByte and the first non-empty token, the
+// server metrics from the final chunk and, failing those, a rate from counting
+// token chunks.
+func (e *Engine) processStream(body io.Reader, firstByte time.Time, res *model.Result) (string, bool, error) {
 	scanner := bufio.NewScanner(body)
 	gotDone := false
+	var text strings.Builder
+	var chunks int // Ollama sends one token per chunk
+	var firstToken, lastToken time.Time
+	var deltas []time.Duration // token_timings only
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -297,8 +780,15 @@ func (e *Engine) processStream(body io.Reader) bool {
 		}
 
 		var chunk struct {
-			Response string `json:"response"`
-			Done     bool   `json:"done"`
+			Response           string `json:"response"`
+			Done               bool   `json:"done"`
+			DoneReason         string `json:"done_reason"`          // final chunk only
+			TotalDuration      int64  `json:"total_duration"`       // ns, final chunk only
+			LoadDuration       int64  `json:"load_duration"`        // ns, final chunk only
+			PromptEvalCount    int    `json:"prompt_eval_count"`    // final chunk only
+			PromptEvalDuration int64  `json:"prompt_eval_duration"` // ns, final chunk only
+			EvalCount          int    `json:"eval_count"`           // final chunk only
+			EvalDuration       int64  `json:"eval_duration"`        // ns, final chunk only
 		}
 
 		// Garbage resilience: Ignore JSON errors
@@ -308,34 +798,147 @@ func (e *Engine) processStream(body io.Reader) bool {
 		}
 
 		if chunk.Response != "" {
-			// In interactive mode we might print, but for now we just verify flow
+			now := time.Now()
+			if e.tokenLog != nil {
+				if chunks == 0 {
+					deltas = append(deltas, now.Sub(firstByte))
+				} else {
+					deltas = append(deltas, now.Sub(lastToken))
+				}
+			}
+			lastToken = now
+			if chunks == 0 {
+				firstToken = lastToken
+				res.TimeToFirstToken = firstToken.Sub(firstByte)
+			}
+			chunks++
+			text.WriteString(chunk.Response)
+		}
+
+		// Interactive mode: echo tokens as they arrive, otherwise we just verify flow
+		if chunk.Response != "" && e.Config.Interactive {
+			fmt.Fprint(os.Stdout, chunk.Response)
+			os.Stdout.Sync()
 		}
 
 		if chunk.Done {
+			res.DoneReason = chunk.DoneReason
+			res.TotalDuration = time.Duration(chunk.TotalDuration)
+			res.LoadDuration = time.Duration(chunk.LoadDuration)
+			res.PromptEvalCount = chunk.PromptEvalCount
+			res.PromptEvalDuration = time.Duration(chunk.PromptEvalDuration)
+			res.EvalCount = chunk.EvalCount
+			res.EvalDuration = time.Duration(chunk.EvalDuration)
+			if e.Config.Interactive {
+				fmt.Fprintln(os.Stdout)
+			}
 			gotDone = true
 			break // Successfully finished
 		}
 	}
 
+	// Prefer the server's counts; fall back to counting chunks over the
+	// client-side generation time (first to last token)
+	res.TokensGenerated = chunks
+	if res.EvalCount > 0 {
+		res.TokensGenerated = res.EvalCount
+	}
+	if res.EvalDuration > 0 {
+		res.TokensPerSecond = float64(res.EvalCount) / res.EvalDuration.Seconds()
+	} else if gen := lastToken.Sub(firstToken); chunks > 1 && gen > 0 {
+		res.TokensPerSecond = float64(chunks-1) / gen.Seconds()
+	}
+	if gotDone && e.tokenLog != nil {
+		if err := e.tokenLog.Write(res.Model, res.URL, deltas); err != nil {
+			output.Logger.Warn("Failed to write token timings", "model", res.Model, "error", err)
+		}
+	}
+	if gotDone && e.Config.Interactive && res.TokensPerSecond > 0 {
+		fmt.Fprintf(os.Stdout, "[%d tokens, %.1f tokens/sec]\n", res.TokensGenerated, res.TokensPerSecond)
+	}
+
 	if err := scanner.Err(); err != nil {
-		output.Logger.Warn("Stream scanning error", "err", err)
-		return false
+		return text.String(), false, err
 	}
+	return text.String(), gotDone, nil
+}
 
-	return gotDone
+// Inference runs a non-streaming benchmark against /api/generate.
+func (e *Engine) Inference(ctx context.Context, baseURL, modelName, prompt string, images []string, extraConfig map[string]interface{}) (model.Result, error) {
+	payload := map[string]interface{}{
+		"model":      modelName,
+		"prompt":     prompt,
+		"stream":     false,
+		"options":    extraConfig,
+		"keep_alive": e.Config.KeepAlive,
+	}
+	if len(images) > 0 {
+		payload["images"] = images
+	}
+	e.setFormat(payload)
+
+	return e.benchmark(ctx, baseURL, modelName, "/api/generate", payload, extraConfig)
 }
 
-// Inference runs a non-streaming benchmark.
-func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[string]interface{}) (model.Result, error) {
-	start := time.Now()
+// Warmup fires a throwaway /api/generate request with a tiny prompt to force the
+// model into VRAM before measured runs. options should match the first measured
+// config so Ollama doesn't reload the model for a different context size.
+func (e *Engine) Warmup(ctx context.Context, baseURL, modelName string, options map[string]interface{}) error {
+	_, err := e.Inference(ctx, baseURL, modelName, "Hi", nil, options)
+	return err
+}
+
+// UnloadModel asks Ollama to evict a model from memory by sending an empty
+// /api/generate request with keep_alive 0. It does not wait for the eviction;
+// use GetRunningModelInfo to confirm the model is gone.
+func (e *Engine) UnloadModel(ctx context.Context, baseURL, modelName string) error {
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"model":      modelName,
+		"keep_alive": 0,
+	})
+
+	req, err := e.newRequest(ctx, "POST", baseURL, "/api/generate", reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Network/Connection Error: %w", err)
+	}
+	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return statusError("Ollama", resp, body)
+	}
+	return nil
+}
+
+// ChatInference runs a non-streaming benchmark against /api/chat.
+// The messages slice carries the full conversation, including the final user turn.
+func (e *Engine) ChatInference(ctx context.Context, baseURL, modelName string, messages []model.Message, extraConfig map[string]interface{}) (model.Result, error) {
 	payload := map[string]interface{}{
 		"model":      modelName,
-		"prompt":     prompt,
+		"messages":   messages,
 		"stream":     false,
 		"options":    extraConfig,
 		"keep_alive": e.Config.KeepAlive,
 	}
+	e.setFormat(payload)
+
+	return e.benchmark(ctx, baseURL, modelName, "/api/chat", payload, extraConfig)
+}
+
+// benchmark POSTs a non-streaming payload to the given API path and collects
+// the server-side timing metrics. /api/generate and /api/chat share the same
+// metric fields; only the location of the response text differs.
+func (e *Engine) benchmark(parent context.Context, baseURL, modelName, path string, payload map[string]interface{}, extraConfig map[string]interface{}) (model.Result, error) {
+	// Queue on the rate limiter before the clock starts
+	if err := e.waitTurn(parent); err != nil {
+		return model.Result{Model: modelName, URL: baseURL, Error: err.Error()}, err
+	}
+	start := time.Now()
 
 	reqBody, _ := json.Marshal(payload)
 	// Result structure to populate
@@ -348,27 +951,34 @@ func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[st
 
 	// Retry loop
 	var lastErr error
+	retryStart := time.Now()
 	for i := 0; i < e.Config.MaxRetries; i++ {
 		if i > 0 {
-			time.Sleep(e.Config.RetryDelay)
-			output.Logger.Info("Retrying inference...", "attempt", i+1)
+			if !e.retryAllowed(retryStart, modelName, lastErr) {
+				break
+			}
+			if err := e.retryWait(parent); err != nil {
+				res.Error = err.Error()
+				return res, err
+			}
+			output.Logger.Info("Retrying inference...", "attempt", i+1, "error", lastErr)
 		}
 
+		var status int
 		finished, resData, abortErr, loopErr := func() (bool, model.Result, error, error) {
-			ctx, cancel := context.WithCancel(context.Background())
-			timeoutCtx, timeoutCancel := context.WithTimeout(ctx, e.Config.LoadTimeout+e.Config.StreamTimeout)
+			ctx, cancel := context.WithCancel(parent)
+			timeoutCtx, timeoutCancel := context.WithTimeout(ctx, e.requestBudget(modelName))
 			defer timeoutCancel()
 			defer cancel()
 
-			// Launch Loading Monitor
-			abort := make(chan error, 1)
-			go e.monitorLoading(timeoutCtx, baseURL, modelName, abort, cancel)
+			// Launch Loading Monitor (stopped before this attempt returns)
+			abort, stopMonitor := e.startMonitor(timeoutCtx, baseURL, modelName, cancel)
+			defer stopMonitor()
 
-			req, err := http.NewRequestWithContext(timeoutCtx, "POST", fmt.Sprintf("%s/api/generate", baseURL), bytes.NewBuffer(reqBody))
+			req, err := e.newRequest(timeoutCtx, "POST", baseURL, path, reqBody)
 			if err != nil {
 				return false, model.Result{}, nil, err
 			}
-			req.Header.Set("Content-Type", "application/json")
 
 			output.Logger.Info("Network: Request Sent. Waiting for model to load...", "model", modelName)
 			resp, err := e.Client.Do(req)
@@ -381,22 +991,23 @@ func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[st
 				}
 
 				// Cruiser Protocol: Classify specific network errors
-				message := err.Error()
-				if strings.Contains(message, "awaiting headers") {
-					return false, model.Result{}, nil, fmt.Errorf("Ollama Header Timeout (model loading?): %w", err)
-				}
-				return false, model.Result{}, nil, fmt.Errorf("Network/Connection Error: %w", err)
+				return false, model.Result{}, nil, e.classifyRequestError(err, "Ollama", modelName)
 			}
 			defer resp.Body.Close()
 
+			status = resp.StatusCode
 			if resp.StatusCode != http.StatusOK {
 				body, _ := io.ReadAll(resp.Body)
-				return false, model.Result{}, nil, fmt.Errorf("Ollama Server Error (%s): %s", resp.Status, string(body))
+				return false, model.Result{}, nil, statusError("Ollama", resp, body)
 			}
 
 			var data struct {
-				Response           string `json:"response"`
+				Response string `json:"response"` // /api/generate
+				Message  struct {
+					Content string `json:"content"`
+				} `json:"message"` // /api/chat
 				Done               bool   `json:"done"`
+				DoneReason         string `json:"done_reason"`    // "stop", or "length" at num_predict
 				TotalDuration      int64  `json:"total_duration"` // ns
 				LoadDuration       int64  `json:"load_duration"`  // ns
 				PromptEvalCount    int    `json:"prompt_eval_count"`
@@ -408,15 +1019,19 @@ func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[st
 
 			bodyBytes, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return false, model.Result{}, nil, fmt.Errorf("failed to read response body: %w", err)
+				return false, model.Result{}, nil, fmt.Errorf("failed to read response body: %w", e.classifyRequestError(err, "Ollama", modelName))
 			}
 
 			if err := json.Unmarshal(bodyBytes, &data); err != nil {
-				return false, model.Result{}, nil, fmt.Errorf("Ollama returned invalid JSON: %w (Body: %s)", err, string(bodyBytes))
+				return false, model.Result{}, nil, withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("Ollama returned invalid JSON: %w (Body: %s)", err, string(bodyBytes)))
 			}
 
 			if data.Error != "" {
-				return false, model.Result{}, nil, fmt.Errorf("Ollama API Error: %s", data.Error)
+				return false, model.Result{}, nil, apiError(resp.StatusCode, data.Error)
+			}
+
+			if data.Response == "" {
+				data.Response = data.Message.Content
 			}
 
 			// Success
@@ -426,6 +1041,7 @@ func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[st
 				Config:             extraConfig,
 				Timestamp:          start,
 				Response:           data.Response,
+				DoneReason:         data.DoneReason,
 				TotalDuration:      time.Duration(data.TotalDuration),
 				LoadDuration:       time.Duration(data.LoadDuration),
 				PromptEvalCount:    data.PromptEvalCount,
@@ -436,15 +1052,280 @@ func (e *Engine) Inference(baseURL, modelName, prompt string, extraConfig map[st
 		}()
 
 		if abortErr != nil {
-			return model.Result{}, abortErr
+			res.Error = abortErr.Error()
+			return res, abortErr
 		}
 		if finished {
 			resData.Duration = time.Since(start) // Calculate overall duration for the successful attempt
 			resData.TokensGenerated = resData.EvalCount
-			resData.TokensReturned = len(strings.Split(resData.Response, " "))
+			resData.ResponseRunes = utf8.RuneCountInString(resData.Response)
+			if resData.EvalDuration > 0 {
+				resData.TokensPerSecond = float64(resData.EvalCount) / resData.EvalDuration.Seconds()
+			}
 			return resData, nil
 		}
 		lastErr = loopErr
+
+		// Interrupted by the caller (e.g. SIGINT): don't retry
+		if parent.Err() != nil {
+			break
+		}
+		if !isRetryable(loopErr, status) {
+			output.Logger.Warn("Not retrying: error is not transient", "model", modelName, "error", loopErr)
+			break
+		}
+	}
+
+	res.Error = lastErr.Error()
+	return res, lastErr
+}
+
+// EmbedInference benchmarks a single /api/embeddings request.
+// Latency is measured client-side and the returned vector dimension is recorded.
+// An empty embedding is treated as a failure rather than a silent success.
+func (e *Engine) EmbedInference(parent context.Context, baseURL, modelName, input string) (model.Result, error) {
+	// Queue on the rate limiter before the clock starts
+	if err := e.waitTurn(parent); err != nil {
+		return model.Result{Model: modelName, URL: baseURL, Error: err.Error()}, err
+	}
+	start := time.Now()
+
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"model":      modelName,
+		"prompt":     input,
+		"keep_alive": e.Config.KeepAlive,
+	})
+
+	res := model.Result{
+		Model:     modelName,
+		URL:       baseURL,
+		Timestamp: start,
+	}
+
+	// Retry loop
+	var lastErr error
+	retryStart := time.Now()
+	for i := 0; i < e.Config.MaxRetries; i++ {
+		if i > 0 {
+			if !e.retryAllowed(retryStart, modelName, lastErr) {
+				break
+			}
+			if err := e.retryWait(parent); err != nil {
+				res.Error = err.Error()
+				return res, err
+			}
+			output.Logger.Info("Retrying embedding...", "attempt", i+1, "error", lastErr)
+		}
+
+		var status int
+		dim, abortErr, loopErr := func() (int, error, error) {
+			ctx, cancel := context.WithCancel(parent)
+			timeoutCtx, timeoutCancel := context.WithTimeout(ctx, e.requestBudget(modelName))
+			defer timeoutCancel()
+			defer cancel()
+
+			// Launch Loading Monitor (stopped before this attempt returns)
+			abort, stopMonitor := e.startMonitor(timeoutCtx, baseURL, modelName, cancel)
+			defer stopMonitor()
+
+			req, err := e.newRequest(timeoutCtx, "POST", baseURL, "/api/embeddings", reqBody)
+			if err != nil {
+				return 0, nil, err
+			}
+
+			resp, err := e.Client.Do(req)
+			if err != nil {
+				select {
+				case abortErr := <-abort:
+					return 0, abortErr, nil
+				default:
+				}
+
+				return 0, nil, e.classifyRequestError(err, "Ollama", modelName)
+			}
+			defer resp.Body.Close()
+
+			status = resp.StatusCode
+			if resp.StatusCode != http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				return 0, nil, statusError("Ollama", resp, body)
+			}
+
+			var data struct {
+				Embedding []float64 `json:"embedding"`
+				Error     string    `json:"error"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+				return 0, nil, withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("Ollama returned invalid JSON: %w", err))
+			}
+			if data.Error != "" {
+				return 0, nil, apiError(resp.StatusCode, data.Error)
+			}
+			if len(data.Embedding) == 0 {
+				return 0, nil, withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("Ollama returned an empty embedding"))
+			}
+			return len(data.Embedding), nil, nil
+		}()
+
+		if abortErr != nil {
+			res.Error = abortErr.Error()
+			return res, abortErr
+		}
+		if loopErr == nil {
+			res.Duration = time.Since(start)
+			res.VectorDim = dim
+			return res, nil
+		}
+		lastErr = loopErr
+
+		// Interrupted by the caller (e.g. SIGINT): don't retry
+		if parent.Err() != nil {
+			break
+		}
+		if !isRetryable(loopErr, status) {
+			output.Logger.Warn("Not retrying: error is not transient", "model", modelName, "error", loopErr)
+			break
+		}
+	}
+
+	res.Error = lastErr.Error()
+	return res, lastErr
+}
+
+// openAIOptions maps Ollama-style inference options onto their OpenAI request
+// equivalents. Options with no OpenAI counterpart (e.g. num_ctx) are dropped.
+func openAIOptions(extraConfig map[string]interface{}) map[string]interface{} {
+	params := make(map[string]interface{})
+	for k, v := range extraConfig {
+		switch k {
+		case "temperature", "top_p", "seed", "stop", "presence_penalty", "frequency_penalty":
+			params[k] = v
+		case "num_predict":
+			params["max_tokens"] = v
+		}
+	}
+	return params
+}
+
+// OpenAIInference runs a non-streaming benchmark against an OpenAI-compatible
+// /v1/chat/completions endpoint. Token counts come from the usage block; since
+// OpenAI responses carry no server-side durations, only the client-measured
+// Duration is populated and the Ollama timing fields are left at zero.
+func (e *Engine) OpenAIInference(parent context.Context, baseURL, modelName string, messages []model.Message, extraConfig map[string]interface{}) (model.Result, error) {
+	// Queue on the rate limiter before the clock starts
+	if err := e.waitTurn(parent); err != nil {
+		return model.Result{Model: modelName, URL: baseURL, Error: err.Error()}, err
+	}
+	start := time.Now()
+
+	payload := openAIOptions(extraConfig)
+	payload["model"] = modelName
+	payload["messages"] = messages
+	payload["stream"] = false
+
+	reqBody, _ := json.Marshal(payload)
+
+	res := model.Result{
+		Model:     modelName,
+		URL:       baseURL,
+		Config:    extraConfig,
+		Timestamp: start,
+	}
+
+	// Retry loop
+	var lastErr error
+	retryStart := time.Now()
+	for i := 0; i < e.Config.MaxRetries; i++ {
+		if i > 0 {
+			if !e.retryAllowed(retryStart, modelName, lastErr) {
+				break
+			}
+			if err := e.retryWait(parent); err != nil {
+				res.Error = err.Error()
+				return res, err
+			}
+			output.Logger.Info("Retrying inference...", "attempt", i+1, "error", lastErr)
+		}
+
+		var status int
+		resData, loopErr := func() (model.Result, error) {
+			ctx, cancel := context.WithTimeout(parent, e.requestBudget(modelName))
+			defer cancel()
+
+			req, err := e.newRequest(ctx, "POST", baseURL, "/v1/chat/completions", reqBody)
+			if err != nil {
+				return model.Result{}, err
+			}
+
+			output.Logger.Info("Network: Request Sent. Waiting for server...", "model", modelName)
+			resp, err := e.Client.Do(req)
+			if err != nil {
+				return model.Result{}, e.classifyRequestError(err, "Server", modelName)
+			}
+			defer resp.Body.Close()
+
+			status = resp.StatusCode
+			bodyBytes, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return model.Result{}, fmt.Errorf("failed to read response body: %w", e.classifyRequestError(err, "Server", modelName))
+			}
+
+			if resp.StatusCode != http.StatusOK {
+				return model.Result{}, statusError("OpenAI", resp, bodyBytes)
+			}
+
+			var data struct {
+				Choices []struct {
+					Message struct {
+						Content string `json:"content"`
+					} `json:"message"`
+					FinishReason string `json:"finish_reason"` // "stop", or "length" at max_tokens
+				} `json:"choices"`
+				Usage struct {
+					PromptTokens     int `json:"prompt_tokens"`
+					CompletionTokens int `json:"completion_tokens"`
+				} `json:"usage"`
+			}
+
+			if err := json.Unmarshal(bodyBytes, &data); err != nil {
+				return model.Result{}, withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("OpenAI backend returned invalid JSON: %w (Body: %s)", err, string(bodyBytes)))
+			}
+			if len(data.Choices) == 0 {
+				return model.Result{}, withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("OpenAI backend returned no choices"))
+			}
+
+			return model.Result{
+				Model:           modelName,
+				URL:             baseURL,
+				Config:          extraConfig,
+				Timestamp:       start,
+				Response:        data.Choices[0].Message.Content,
+				DoneReason:      data.Choices[0].FinishReason,
+				PromptEvalCount: data.Usage.PromptTokens,
+				EvalCount:       data.Usage.CompletionTokens,
+			}, nil
+		}()
+
+		if loopErr == nil {
+			resData.Duration = time.Since(start)
+			resData.TokensGenerated = resData.EvalCount
+			resData.ResponseRunes = utf8.RuneCountInString(resData.Response)
+			// No server-side eval duration; fall back to client wall time
+			if resData.Duration > 0 {
+				resData.TokensPerSecond = float64(resData.EvalCount) / resData.Duration.Seconds()
+			}
+			return resData, nil
+		}
+		lastErr = loopErr
+
+		// Interrupted by the caller (e.g. SIGINT): don't retry
+		if parent.Err() != nil {
+			break
+		}
+		if !isRetryable(loopErr, status) {
+			output.Logger.Warn("Not retrying: error is not transient", "model", modelName, "error", loopErr)
+			break
+		}
 	}
 
 	res.Error = lastErr.Error()
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+func TestGetRunningModelInfo(t *testing.T) {
+	srv := newFakeOllama(t)
+	srv.running = []runningModel{
+		{Name: "llama3:8b", Size: 8 << 30, SizeVRAM: 6 << 30},
+		{Name: "llama3.1:8b", Size: 9 << 30, SizeVRAM: 9 << 30},
+	}
+	e := New(testConfig(t, srv.URL))
+
+	tests := []struct {
+		name      string
+		model     string
+		size      int64
+		sizeVRAM  int64
+		ambiguous bool
+	}{
+		{name: "exact", model: "llama3:8b", size: 8 << 30, sizeVRAM: 6 << 30},
+		{name: "exact other", model: "llama3.1:8b", size: 9 << 30, sizeVRAM: 9 << 30},
+		{name: "unique prefix", model: "llama3.1", size: 9 << 30, sizeVRAM: 9 << 30},
+		{name: "ambiguous prefix", model: "llama3", ambiguous: true},
+		{name: "not loaded", model: "mistral"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, sizeVRAM, err := e.GetRunningModelInfo(context.Background(), srv.URL, tt.model)
+			if tt.ambiguous {
+				if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+					t.Fatalf("GetRunningModelInfo(%q) err = %v, want ambiguous match error", tt.model, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != tt.size || sizeVRAM != tt.sizeVRAM {
+				t.Fatalf("GetRunningModelInfo(%q) = %d, %d; want %d, %d", tt.model, size, sizeVRAM, tt.size, tt.sizeVRAM)
+			}
+		})
+	}
+}
+
+func TestStreamInferenceRetriesAfterFailure(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b")
+	srv.failNext("/api/generate", 1)
+	e := New(testConfig(t, srv.URL, "llama3:8b"))
+
+	var res model.Result
+	if _, err := e.StreamInference(context.Background(), srv.URL, "llama3:8b", "hi", nil, nil, &res); err != nil {
+		t.Fatalf("StreamInference after one 500: %v", err)
+	}
+	if got := srv.hitCount("/api/generate"); got != 2 {
+		t.Fatalf("/api/generate got %d requests, want 2 (one failure, one retry)", got)
+	}
+	if res.Response != "hello world" || res.EvalCount != 20 {
+		t.Fatalf("retried attempt result = %q with eval_count %d, want the second attempt's stream", res.Response, res.EvalCount)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+PURPOSE:
+  Context size sweep: measures how generation speed changes as the context
+  grows, without hand-writing one inference config per num_ctx.
+
+REQUIREMENTS:
+  User-specified:
+  - ctx_sweep (e.g. [2048, 4096, 8192]) generates configs varying num_ctx.
+  - The prompt is padded with filler to roughly fill each context.
+  - The effective prompt size (prompt_eval_count) is recorded per row.
+
+  Implementation-discovered:
+  - Every inference config is crossed with every sweep size; the sweep size
+    replaces any num_ctx the config set. Configs that become identical are
+    kept once.
+  - Padding goes before the prompt so the real question is still the last
+    thing the model reads.
+  - Token counts are estimated (no tokenizer here); the filler sentence is
+    about fillerTokens tokens on common tokenizers. prompt_eval_count in the
+    result is the real figure to plot against.
+  - A share of the context is left free for generation: num_predict when set,
+    otherwise a quarter of num_ctx. Overfilling would make Ollama truncate the
+    prompt from the front, dropping the question.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run, pendingRuns), plan.go
+
+ERROR HANDLING:
+  - None; validation of sweep sizes lives in config.Validate.
+
+IMPLEMENTATION RULES:
+  - Applied before max_tokens so the generated configs get the cap too.
+  - Padding is per (prompt, config); the prompt name is unchanged so --resume
+    and compare line rows up as before.
+
+USAGE:
+  applyCtxSweep(cfg)
+  text := padPrompt(prompt.Text, inferCfg)
+
+SELF-HEALING INSTRUCTIONS:
+  - prompt_eval_count far below num_ctx: the model's own context limit is
+    smaller than the sweep size; Ollama clamps num_ctx to it.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/config/config.go
+
+MAINTENANCE:
+  - Re-check fillerTokens if the filler sentence changes.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+)
+
+const (
+	// filler is repeated ahead of the prompt to fill the context
+	filler = "The quick brown fox jumps over the lazy dog near the quiet river bank. "
+	// fillerTokens is the approximate token count of one filler sentence
+	fillerTokens = 15
+	// charsPerToken estimates the prompt's own token count from its length
+	charsPerToken = 4
+)
+
+// applyCtxSweep replaces cfg.InferConfigs with one config per (config, sweep
+// size) pair, num_ctx set to the sweep size. A no-op without ctx_sweep.
+func applyCtxSweep(cfg *config.Config) {
+	if len(cfg.CtxSweep) == 0 {
+		return
+	}
+	bases := cfg.InferConfigs
+	if len(bases) == 0 {
+		bases = []map[string]interface{}{{}}
+	}
+
+	var configs []map[string]interface{}
+	seen := make(map[string]bool)
+	for _, base := range bases {
+		for _, size := range cfg.CtxSweep {
+			swept := make(map[string]interface{}, len(base)+1)
+			for k, v := range base {
+				swept[k] = v
+			}
+			swept["num_ctx"] = size
+			// fmt prints maps with sorted keys, so equal configs share a key
+			if key := fmt.Sprint(swept); !seen[key] {
+				seen[key] = true
+				configs = append(configs, swept)
+			}
+		}
+	}
+	cfg.InferConfigs = configs
+}
+
+// padPrompt prefixes text with filler so it roughly fills inferCfg's num_ctx,
+// leaving room for generation. Returns text unchanged when it already fills
+// the target or the config sets no num_ctx.
+func padPrompt(text string, inferCfg map[string]interface{}) string {
+	numCtx := optionInt(inferCfg, "num_ctx")
+	if numCtx <= 0 {
+		return text
+	}
+	reserve := numCtx / 4
+	if n := optionInt(inferCfg, "num_predict"); n > 0 && n < numCtx {
+		reserve = n
+	}
+	repeats := (numCtx - reserve - len(text)/charsPerToken) / fillerTokens
+	if repeats <= 0 {
+		return text
+	}
+	return strings.Repeat(filler, repeats) + "\n\n" + text
+}
+
+// optionInt reads an integer option; YAML and JSON may decode it as int or float64.
+func optionInt(opts map[string]interface{}, key string) int {
+	switch v := opts[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,133 @@
+/*
+PURPOSE:
+  Categorizes request failures so results carry a structured error kind and
+  HTTP status next to the free-text error (e.g. GROUP BY error_kind).
+
+REQUIREMENTS:
+  User-specified:
+  - Kinds: network, timeout, api, placement_abort, server_5xx, client_4xx,
+    context_exceeded, vram_headroom.
+  - Record the HTTP status when the server answered with one.
+
+  Implementation-discovered:
+  - Errors are tagged where they are created (kindError) and read back with
+    errors.As, so wrapping ("stream interrupted: ...") keeps the tag.
+  - In-band {"error": ...} replies (errAPI) and malformed bodies are "api".
+  - context_exceeded comes from the server's "exceeds the context length"
+    error, or from a successful reply whose prompt_eval_count reached the
+    configured num_ctx: Ollama truncates an oversized prompt to exactly
+    num_ctx tokens instead of failing. Never retried.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/client.go, pull.go, runner.go
+  - Uses: optionInt (ctxsweep.go) to read num_ctx
+
+ERROR HANDLING:
+  - Untagged errors report an empty kind rather than a guess.
+
+IMPLEMENTATION RULES:
+  - Error() text is unchanged by tagging; the error column reads as before.
+
+USAGE:
+  return withKind(ErrorKindTimeout, 0, err)
+  res.ErrorKind, res.StatusCode = errorDetails(err)
+
+SELF-HEALING INSTRUCTIONS:
+  - Failures with an empty error_kind: tag the error site that produced them.
+
+RELATED FILES:
+  - internal/engine/client.go
+  - internal/model/types.go
+
+MAINTENANCE:
+  - New error sites must use withKind or statusError.
+*/
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// Error kinds recorded in model.Result.ErrorKind.
+const (
+	ErrorKindNetwork   = "network"
+	ErrorKindTimeout   = "timeout"
+	ErrorKindAPI       = "api"
+	ErrorKindPlacement = "placement_abort"
+	ErrorKindServer    = "server_5xx"
+	ErrorKindClient    = "client_4xx"
+	ErrorKindContext   = "context_exceeded"
+	ErrorKindHeadroom  = "vram_headroom"
+)
+
+// kindError tags an error with its kind and, if any, the HTTP status.
+type kindError struct {
+	kind   string
+	status int
+	err    error
+}
+
+func (k *kindError) Error() string { return k.err.Error() }
+func (k *kindError) Unwrap() error { return k.err }
+
+// withKind tags err with a kind and HTTP status (0 when there was no response).
+func withKind(kind string, status int, err error) error {
+	return &kindError{kind: kind, status: status, err: err}
+}
+
+// statusError reports a non-2xx response, tagged client_4xx or server_5xx
+// (context_exceeded when the body says the input didn't fit).
+func statusError(server string, resp *http.Response, body []byte) error {
+	kind := ErrorKindServer
+	if resp.StatusCode < 500 {
+		kind = ErrorKindClient
+	}
+	if isContextError(string(body)) {
+		kind = ErrorKindContext
+	}
+	return withKind(kind, resp.StatusCode, fmt.Errorf("%s Server Error (%s): %s", server, resp.Status, string(body)))
+}
+
+// apiError reports an in-band {"error": msg} reply, tagged api or context_exceeded.
+func apiError(status int, msg string) error {
+	kind := ErrorKindAPI
+	if isContextError(msg) {
+		kind = ErrorKindContext
+	}
+	return withKind(kind, status, fmt.Errorf("%w: %s", errAPI, msg))
+}
+
+// isContextError matches the server's "input length exceeds the context length".
+func isContextError(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "exceeds the context length") || strings.Contains(msg, "exceeds context length")
+}
+
+// contextExceeded returns a context_exceeded error when a successful reply's
+// prompt filled the whole configured num_ctx, i.e. the server truncated it.
+// Without num_ctx in the config the server default applies and can't be checked.
+func contextExceeded(res model.Result, inferCfg map[string]interface{}) error {
+	numCtx := optionInt(inferCfg, "num_ctx")
+	if numCtx <= 0 || res.PromptEvalCount < numCtx {
+		return nil
+	}
+	return withKind(ErrorKindContext, 0, fmt.Errorf("prompt did not fit: prompt_eval_count %d reached num_ctx %d, so the server truncated it", res.PromptEvalCount, numCtx))
+}
+
+// errorDetails returns the kind and HTTP status recorded for a failed request.
+func errorDetails(err error) (string, int) {
+	var k *kindError
+	if errors.As(err, &k) {
+		return k.kind, k.status
+	}
+	if errors.Is(err, errAPI) {
+		return ErrorKindAPI, 0
+	}
+	return "", 0
+}
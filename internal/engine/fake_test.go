@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+func TestMain(m *testing.M) {
+	output.SetLogger(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// fakeOllama is an httptest Ollama with just enough API for the engine:
+// tags, ps, version, show, generate (streaming or not), chat and embeddings.
+type fakeOllama struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	models   []string
+	running  []runningModel              // /api/ps; a generate request loads its model
+	noPS     bool                        // /api/ps answers 404
+	delay    time.Duration               // Inference requests wait this long (or until cancelled)
+	failures map[string]int              // path -> 500s left to send before succeeding
+	hits     map[string]int              // path -> requests received
+	bodies   map[string][]map[string]any // path -> decoded POST bodies
+}
+
+// newFakeOllama starts a fake server listing models; it is closed with the test.
+func newFakeOllama(t *testing.T, models ...string) *fakeOllama {
+	t.Helper()
+	f := &fakeOllama{
+		models:   models,
+		failures: make(map[string]int),
+		hits:     make(map[string]int),
+		bodies:   make(map[string][]map[string]any),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// failNext makes the next n requests to path answer 500.
+func (f *fakeOllama) failNext(path string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[path] = n
+}
+
+// hitCount returns how many requests path has received.
+func (f *fakeOllama) hitCount(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits[path]
+}
+
+// requests returns the decoded POST bodies sent to path.
+func (f *fakeOllama) requests(path string) []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.bodies[path]...)
+}
+
+func (f *fakeOllama) serve(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if r.Method == http.MethodPost {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	f.mu.Lock()
+	f.hits[r.URL.Path]++
+	if body != nil {
+		f.bodies[r.URL.Path] = append(f.bodies[r.URL.Path], body)
+	}
+	if f.failures[r.URL.Path] > 0 {
+		f.failures[r.URL.Path]--
+		f.mu.Unlock()
+		http.Error(w, `{"error":"injected failure"}`, http.StatusInternalServerError)
+		return
+	}
+	f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/api/tags":
+		var models []map[string]any
+		for _, m := range f.models {
+			models = append(models, map[string]any{"name": m, "size": 1 << 30})
+		}
+		writeJSON(w, map[string]any{"models": models})
+	case "/api/version":
+		writeJSON(w, map[string]any{"version": "0.0.0-test"})
+	case "/api/ps":
+		f.mu.Lock()
+		noPS, running := f.noPS, append([]runningModel(nil), f.running...)
+		f.mu.Unlock()
+		if noPS {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]any{"models": running})
+	case "/api/show":
+		writeJSON(w, map[string]any{"capabilities": []string{"completion"}, "model_info": map[string]any{"llama.context_length": 8192}})
+	case "/api/embeddings":
+		if !f.wait(r) {
+			return
+		}
+		writeJSON(w, map[string]any{"embedding": []float64{0.1, 0.2, 0.3}})
+	case "/api/generate", "/api/chat":
+		name, _ := body["model"].(string)
+		f.load(name)
+		if !f.wait(r) {
+			return
+		}
+		metrics := map[string]any{
+			"done": true, "done_reason": "stop", "total_duration": 2e8, "load_duration": 1e7,
+			"prompt_eval_count": 10, "prompt_eval_duration": 5e6, "eval_count": 20, "eval_duration": 1e8,
+		}
+		if stream, _ := body["stream"].(bool); stream {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			for _, tok := range []string{"hello ", "world"} {
+				fmt.Fprintf(w, `{"response":%q,"done":false}`+"\n", tok)
+			}
+			json.NewEncoder(w).Encode(metrics)
+			return
+		}
+		if r.URL.Path == "/api/chat" {
+			metrics["message"] = map[string]any{"role": "assistant", "content": "hello world"}
+		} else {
+			metrics["response"] = "hello world"
+		}
+		writeJSON(w, metrics)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// wait holds an inference request for f.delay, as a loading model would.
+// It reports false if the client gave up first.
+func (f *fakeOllama) wait(r *http.Request) bool {
+	f.mu.Lock()
+	delay := f.delay
+	f.mu.Unlock()
+	if delay <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(delay):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// load marks a model as resident in /api/ps, fully in VRAM.
+func (f *fakeOllama) load(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.running {
+		if m.Name == name {
+			return
+		}
+	}
+	f.running = append(f.running, runningModel{Name: name, Size: 1 << 30, SizeVRAM: 1 << 30})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// testConfig returns a fast single-config run against url writing JSON results
+// to a temporary directory.
+func testConfig(t *testing.T, url string, models ...string) *config.Config {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.URLs = []string{url}
+	cfg.Models = models
+	cfg.OutputDir = t.TempDir()
+	cfg.Outputs = []string{config.OutputJSON}
+	cfg.InferConfigs = []map[string]interface{}{{"num_ctx": 2048}}
+	cfg.MaxRetries = 2
+	cfg.RetryDelay = 10 * time.Millisecond
+	cfg.InterRunDelay = 0
+	cfg.MonitorInterval = 20 * time.Millisecond
+	return cfg
+}
@@ -0,0 +1,75 @@
+/*
+PURPOSE:
+  Benchmarks Ollama's structured-output mode. Constrained decoding costs
+  differ from free text, so format runs measure both its speed and how often
+  the model actually returns parseable JSON.
+
+REQUIREMENTS:
+  User-specified:
+  - format: "json" or a JSON schema object, passed through in the payload.
+  - Each response is checked for valid JSON (Result.ValidJSON).
+
+  Implementation-discovered:
+  - The format goes on every generate and chat request, including the stream
+    test, so TTFT and stream_benchmark rows reflect the structured mode too.
+  - A schema is only checked for JSON syntax, not conformance: Ollama
+    enforces the schema itself, and the interesting failure is truncated or
+    non-JSON output (e.g. a num_predict cap cutting an object short).
+  - ValidJSON is a pointer so "not checked" (no format, failed run, OpenAI
+    backend) stays distinct from "invalid".
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/client.go (Inference, ChatInference,
+    StreamInference), runner.go (runConfig, runModel)
+
+ERROR HANDLING:
+  - Invalid JSON is not an error: the run is recorded with valid_json=false
+    and a warning is logged.
+
+IMPLEMENTATION RULES:
+  - The response is checked before writeResult trims it for output.
+
+USAGE:
+  e.setFormat(payload)
+  checkJSON(cfg, url, &res)
+
+SELF-HEALING INSTRUCTIONS:
+  - Every row invalid with a schema: older Ollama releases only accept "json".
+
+RELATED FILES:
+  - internal/config/config.go (Format)
+  - internal/model/types.go (Result.ValidJSON)
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// setFormat adds the configured structured-output format to an Ollama payload.
+func (e *Engine) setFormat(payload map[string]interface{}) {
+	if e.Config.StructuredOutput() {
+		payload["format"] = e.Config.Format
+	}
+}
+
+// checkJSON records in res whether a successful response parses as JSON.
+// It does nothing unless a format was sent with the request.
+func checkJSON(cfg *config.Config, url string, res *model.Result) {
+	if !cfg.StructuredOutput() || cfg.ProtocolFor(url) != config.ProtocolOllama || res.Error != "" {
+		return
+	}
+	valid := json.Valid([]byte(res.Response))
+	res.ValidJSON = &valid
+	if !valid {
+		output.Logger.Warn("Response is not valid JSON", "model", res.Model, "url", url, "config", res.Config)
+	}
+}
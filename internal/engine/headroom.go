@@ -0,0 +1,115 @@
+/*
+PURPOSE:
+  Refuses to load a model that obviously won't fit in the host's free VRAM,
+  so the benchmark never triggers an OOM or CPU-spill load that destabilizes
+  the host. monitorLoading catches bad placement after a load starts; this
+  check runs before it.
+
+REQUIREMENTS:
+  User-specified:
+  - Opt-in via config (max_vram_bytes > 0).
+  - Free VRAM is max_vram_bytes minus the current usage reported by /api/ps.
+  - A model larger than that is skipped with an "insufficient VRAM headroom"
+    result.
+
+  Implementation-discovered:
+  - /api/show reports no byte size, so the model size comes from /api/tags
+    (the weights on disk). The KV cache comes on top, so this is a lower
+    bound: it catches the obvious misfits and leaves the rest to the guards.
+  - A model that is already loaded needs no new load and always passes; its
+    own share of /api/ps is not counted as in use.
+  - Ollama may evict idle models to make room, but only when it can; counting
+    them keeps the check conservative (pair with unload_after to keep hosts
+    empty between models).
+  - OpenAI-compatible backends, and Ollama releases without /api/ps, are not
+    checked.
+  - With model_concurrency > 1 the checks of parallel workers can race; each
+    sees the usage before the other's load.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (runModel)
+  - Uses: runningModels, GetModelSizes (client.go)
+
+ERROR HANDLING:
+  - Returns a vram_headroom error when the model doesn't fit.
+  - If /api/ps or /api/tags fail, or the size is unknown, it logs a warning
+    and lets the model run: the check must never block a benchmark on its own.
+
+IMPLEMENTATION RULES:
+  - Read-only; one /api/ps and one /api/tags request per model.
+
+USAGE:
+  if err := checkHeadroom(ctx, e, cfg, url, modelName); err != nil { ... }
+
+SELF-HEALING INSTRUCTIONS:
+  - Every model skipped: max_vram_bytes is probably in GB rather than bytes.
+
+RELATED FILES:
+  - internal/engine/client.go (monitorLoading)
+  - internal/engine/errors.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// checkHeadroom returns a vram_headroom error if modelName's size exceeds
+// cfg.MaxVRAMBytes minus the VRAM currently in use on url.
+func checkHeadroom(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string) error {
+	if cfg.MaxVRAMBytes <= 0 || cfg.ProtocolFor(url) != config.ProtocolOllama {
+		return nil
+	}
+
+	running, err := e.runningModels(ctx, url)
+	if errors.Is(err, errNoPS) {
+		return nil // Logged once when the host was found to lack /api/ps
+	}
+	if err != nil {
+		output.Logger.Warn("VRAM headroom check skipped: could not read /api/ps", "model", modelName, "url", url, "error", err)
+		return nil
+	}
+	var inUse int64
+	for _, m := range running {
+		if withTag(m.Name) == withTag(modelName) {
+			return nil // Already loaded; nothing new to fit
+		}
+		inUse += m.SizeVRAM
+	}
+
+	sizes, err := e.GetModelSizes(ctx, url)
+	if err != nil {
+		output.Logger.Warn("VRAM headroom check skipped: could not read model sizes", "model", modelName, "url", url, "error", err)
+		return nil
+	}
+	size, ok := sizes[modelName]
+	if !ok {
+		size = sizes[withTag(modelName)]
+	}
+	if size == 0 {
+		output.Logger.Warn("VRAM headroom check skipped: model size unknown", "model", modelName, "url", url)
+		return nil
+	}
+
+	free := cfg.MaxVRAMBytes - inUse
+	if size > free {
+		return withKind(ErrorKindHeadroom, 0, fmt.Errorf("insufficient VRAM headroom: model needs %s, %s free (max_vram_bytes %s, %s in use)",
+			gib(size), gib(max(free, 0)), gib(cfg.MaxVRAMBytes), gib(inUse)))
+	}
+	output.Logger.Debug("VRAM headroom ok", "model", modelName, "url", url, "size", gib(size), "free", gib(free))
+	return nil
+}
+
+// gib formats a byte count in GiB for log and error messages.
+func gib(n int64) string {
+	return fmt.Sprintf("%.1f GiB", float64(n)/(1<<30))
+}
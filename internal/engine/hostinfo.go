@@ -0,0 +1,111 @@
+/*
+PURPOSE:
+  Collects per-backend server information (Ollama version, loaded models, VRAM in use)
+  so VRAM percentages and performance changes can be put in context later.
+
+REQUIREMENTS:
+  User-specified:
+  - Record the Ollama version per URL; capture VRAM info when /api/ps exposes it.
+  - Fail soft on older servers that 404 these endpoints.
+
+  Implementation-discovered:
+  - /api/ps reports size_vram per loaded model but not the GPU's total VRAM,
+    so the sum across loaded models is recorded instead.
+  - OpenAI-compatible backends have neither endpoint; only the protocol is recorded.
+  - Both requests are bounded by discovery_timeout, so a hung host costs
+    seconds here rather than the inference budget before discovery even starts.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run, once per URL before its models)
+  - Produces: internal/model.HostInfo
+
+ERROR HANDLING:
+  - A 404 leaves the field empty without an error. A 404 on /api/ps also
+    marks the URL so nothing polls it again during the run (client.go).
+  - Connection failures are returned and also noted in HostInfo.Error.
+
+IMPLEMENTATION RULES:
+  - Never fails the run; the caller logs and continues.
+
+USAGE:
+  info, err := e.GetHostInfo(ctx, "http://localhost:11434")
+
+SELF-HEALING INSTRUCTIONS:
+  - Empty version with no error: the server predates /api/version.
+
+RELATED FILES:
+  - internal/engine/client.go
+  - internal/output/hosts.go
+
+MAINTENANCE:
+  - Add fields here if Ollama starts exposing GPU totals.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// GetHostInfo queries /api/version and /api/ps on an Ollama host.
+// Missing endpoints are skipped; the returned info is usable even when err != nil.
+func (e *Engine) GetHostInfo(ctx context.Context, baseURL string) (model.HostInfo, error) {
+	info := model.HostInfo{
+		URL:         baseURL,
+		Protocol:    e.Config.ProtocolFor(baseURL),
+		CollectedAt: time.Now(),
+	}
+	if info.Protocol != config.ProtocolOllama {
+		return info, nil
+	}
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := e.getJSON(ctx, baseURL, "/api/version", &version); err != nil {
+		info.Error = err.Error()
+		return info, err
+	}
+	info.Version = version.Version
+
+	running, err := e.runningModels(ctx, baseURL)
+	if errors.Is(err, errNoPS) {
+		return info, nil
+	}
+	if err != nil {
+		info.Error = err.Error()
+		return info, err
+	}
+	for _, m := range running {
+		info.LoadedModels = append(info.LoadedModels, m.Name)
+		info.VRAMInUseBytes += m.SizeVRAM
+	}
+	return info, nil
+}
+
+// getJSON decodes a GET response into v. A 404 leaves v untouched and is not an error.
+func (e *Engine) getJSON(ctx context.Context, baseURL, path string, v interface{}) error {
+	ctx, cancel := e.discoveryContext(ctx)
+	defer cancel()
+	resp, err := e.get(ctx, baseURL, path)
+	if err != nil {
+		return e.discoveryErr(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status: %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
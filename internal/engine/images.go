@@ -0,0 +1,136 @@
+/*
+PURPOSE:
+  Attaches image inputs to prompts so multimodal (vision) models such as llava
+  can be benchmarked on what they are for, and skips models that can't see.
+
+REQUIREMENTS:
+  User-specified:
+  - images: file paths, base64-encoded into the /api/generate "images" array.
+  - Record image count and total bytes in the result.
+  - Multi-image prompts send all images; image_sweep sends one image per run.
+  - Skip a model with a logged note if /api/show says it isn't multimodal.
+
+  Implementation-discovered:
+  - Files are read and encoded once, in loadPrompts, so a bad path fails the
+    run before any model loads and every request reuses the same payload.
+  - image_sweep crosses prompts with images and names each pair
+    "<prompt>+<image file>" (just the file for the single prompt), so
+    prompt_name tells the rows apart and --resume keys stay distinct.
+  - /api/show lists "vision" under capabilities on current Ollama; older
+    releases are recognized by a projector (projector_info) or a clip/mllama
+    family. When /api/show fails, the model is benchmarked anyway.
+  - Only the generate endpoint on Ollama backends sends images; config
+    warnings flag the other combinations.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/prompts.go (loadPrompts), runner.go (runModel)
+  - Uses: showModel (client.go)
+
+ERROR HANDLING:
+  - Returns error if an image file can't be read.
+
+IMPLEMENTATION RULES:
+  - Image bytes count the files on disk, not the base64 payload.
+
+USAGE:
+  prompts, err := withImages(cfg, prompts)
+  ok, err := e.IsMultimodal(ctx, url, modelName)
+
+SELF-HEALING INSTRUCTIONS:
+  - A vision model skipped as "not multimodal": check /api/show's capabilities
+    and families on that host; update visionFamilies if a new projector family appears.
+
+RELATED FILES:
+  - internal/engine/prompts.go
+  - internal/engine/client.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+)
+
+// visionFamilies are model families that carry an image projector.
+var visionFamilies = map[string]bool{"clip": true, "mllama": true}
+
+// withImages attaches cfg.Images to the prompts: all of them to every prompt,
+// or with image_sweep one per prompt/image pair.
+func withImages(cfg *config.Config, prompts []namedPrompt) ([]namedPrompt, error) {
+	if len(cfg.Images) == 0 {
+		return prompts, nil
+	}
+
+	encoded := make([]string, len(cfg.Images))
+	sizes := make([]int64, len(cfg.Images))
+	for i, path := range cfg.Images {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read image: %w", err)
+		}
+		encoded[i] = base64.StdEncoding.EncodeToString(data)
+		sizes[i] = int64(len(data))
+	}
+
+	if !cfg.ImageSweep {
+		var total int64
+		for _, n := range sizes {
+			total += n
+		}
+		for i := range prompts {
+			prompts[i].Images = encoded
+			prompts[i].imageBytes = total
+		}
+		return prompts, nil
+	}
+
+	swept := make([]namedPrompt, 0, len(prompts)*len(encoded))
+	for _, p := range prompts {
+		for i, img := range encoded {
+			run := p
+			run.Name = filepath.Base(cfg.Images[i])
+			if p.Name != "" {
+				run.Name = p.Name + "+" + run.Name
+			}
+			run.Images = []string{img}
+			run.imageBytes = sizes[i]
+			swept = append(swept, run)
+		}
+	}
+	return swept, nil
+}
+
+// IsMultimodal reports whether a model accepts images, per /api/show.
+func (e *Engine) IsMultimodal(ctx context.Context, baseURL, modelName string) (bool, error) {
+	info, err := e.showModel(ctx, baseURL, modelName)
+	if err != nil {
+		return false, err
+	}
+	if len(info.Capabilities) > 0 {
+		for _, c := range info.Capabilities {
+			if strings.EqualFold(c, "vision") {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	if len(info.ProjectorInfo) > 0 {
+		return true, nil
+	}
+	for _, f := range append([]string{info.Details.Family}, info.Details.Families...) {
+		if visionFamilies[strings.ToLower(f)] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
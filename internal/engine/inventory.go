@@ -0,0 +1,114 @@
+/*
+PURPOSE:
+  Builds an inventory of what is deployed where: every model on every
+  configured backend with its size, family, quantization and context length,
+  without running inference.
+
+REQUIREMENTS:
+  User-specified:
+  - Discovery across all URLs via /api/tags + /api/show; no inference.
+  - Honor include/exclude filters so the inventory matches what run would test.
+
+  Implementation-discovered:
+  - /api/tags already carries family, parameter size and quantization; only
+    the trained context length needs /api/show (one request per model).
+  - The context length key is architecture-specific ("llama.context_length"),
+    so any model_info key ending in ".context_length" is used.
+  - OpenAI-compatible backends list names only; their other columns stay empty.
+  - Unreachable backends are logged and skipped so one dead host doesn't hide
+    the rest of the fleet.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/inventory.go
+  - Uses: ListModels (filters), showModel
+
+ERROR HANDLING:
+  - Returns error for an invalid filter, or when no backend could be listed.
+  - A failed /api/show only leaves that model's context length empty.
+
+IMPLEMENTATION RULES:
+  - Entries are in config URL order, then by model name.
+
+USAGE:
+  entries, err := e.Inventory(ctx)
+
+SELF-HEALING INSTRUCTIONS:
+  - Empty context_length everywhere: the server predates model_info in /api/show.
+
+RELATED FILES:
+  - internal/cli/inventory.go
+  - internal/output/inventory.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// Inventory lists the filtered models on every configured URL.
+func (e *Engine) Inventory(ctx context.Context) ([]model.InventoryEntry, error) {
+	var entries []model.InventoryEntry
+	listed := 0
+	for _, url := range e.Config.URLs {
+		models, _, err := e.ListModels(ctx, url)
+		if err != nil {
+			if ctx.Err() != nil {
+				return entries, ctx.Err()
+			}
+			output.Logger.Warn("Could not list models", "url", url, "error", err)
+			continue
+		}
+		listed++
+		sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+
+		for _, m := range models {
+			entry := model.InventoryEntry{
+				URL:           url,
+				Model:         m.Name,
+				Size:          m.Size,
+				ModifiedAt:    m.ModifiedAt,
+				Family:        m.Details.Family,
+				ParameterSize: m.Details.ParameterSize,
+				Quantization:  m.Details.QuantizationLevel,
+			}
+			if e.Config.ProtocolFor(url) == config.ProtocolOllama {
+				n, err := e.ContextLength(ctx, url, m.Name)
+				if err != nil {
+					output.Logger.Warn("Could not read model details", "url", url, "model", m.Name, "error", err)
+				}
+				entry.ContextLength = n
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if listed == 0 && len(e.Config.URLs) > 0 {
+		return nil, fmt.Errorf("no backend could be listed")
+	}
+	return entries, nil
+}
+
+// ContextLength returns the context length a model was trained with, from
+// /api/show. 0 means the server didn't report one.
+func (e *Engine) ContextLength(ctx context.Context, baseURL, modelName string) (int, error) {
+	info, err := e.showModel(ctx, baseURL, modelName)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range info.ModelInfo {
+		if n, ok := v.(float64); ok && strings.HasSuffix(k, ".context_length") {
+			return int(n), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,196 @@
+/*
+PURPOSE:
+  Load test mode: measures how much throughput one model sustains under N
+  simultaneous requests, rather than single-request speed.
+
+REQUIREMENTS:
+  User-specified:
+  - Fire concurrent requests at one model for a fixed duration.
+  - Report aggregate tokens/sec, p95 latency and error rate.
+  - Reuse the Engine's client with a dedicated worker pool.
+
+  Implementation-discovered:
+  - Uses the first inference config and rotates through the prompts, so the
+    load matches what `run` would send.
+  - Retries are disabled: a retried request would hide the failure and count
+    its latency twice. Failures are counted by error kind instead.
+  - The duration bounds when requests start; requests in flight at the end
+    are allowed to finish, and wall time runs until the last one does.
+  - Aggregate tokens/sec is total generated tokens over wall time, which is
+    what serving capacity means; per-request tokens/sec is reported beside it.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/loadtest.go
+  - Uses: runInference (same protocol/endpoint dispatch as Run)
+
+ERROR HANDLING:
+  - Returns error for invalid options, the embeddings endpoint, or prompts
+    that fail to load or render. Request failures are counted, not returned.
+
+IMPLEMENTATION RULES:
+  - Nothing is written to result files; this is a separate measurement.
+
+USAGE:
+  report, err := engine.LoadTest(ctx, cfg, url, "llama3:8b", engine.LoadTestOptions{Concurrency: 8, Duration: time.Minute})
+
+SELF-HEALING INSTRUCTIONS:
+  - Aggregate tokens/sec flat as concurrency grows: raise OLLAMA_NUM_PARALLEL
+    on the server, otherwise requests queue behind each other.
+
+RELATED FILES:
+  - internal/cli/loadtest.go
+  - internal/model/stats.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// LoadTestOptions sets the shape of a load test.
+type LoadTestOptions struct {
+	Concurrency int           // Simultaneous in-flight requests
+	Duration    time.Duration // How long new requests keep being started
+}
+
+// LoadTestReport summarizes a load test against one model.
+type LoadTestReport struct {
+	Model           string
+	URL             string
+	Config          map[string]interface{}
+	Concurrency     int
+	Wall            time.Duration // First request start to last request end
+	Requests        int
+	Errors          int
+	ErrorKinds      map[string]int // error_kind -> count ("other" when untagged)
+	Tokens          int            // Generated tokens across successful requests
+	TokensPerSecond float64        // Tokens / Wall: sustained serving throughput
+	PerRequestTPS   model.Stats    // Tokens/sec of individual requests
+	Latency         model.LatencyStats
+}
+
+// ErrorRate is the fraction of requests that failed.
+func (r LoadTestReport) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// LoadTest keeps opts.Concurrency requests in flight against modelName on url
+// for opts.Duration and summarizes the outcome.
+func LoadTest(ctx context.Context, cfg *config.Config, url, modelName string, opts LoadTestOptions) (LoadTestReport, error) {
+	if opts.Concurrency < 1 {
+		return LoadTestReport{}, fmt.Errorf("concurrency must be at least 1 (got %d)", opts.Concurrency)
+	}
+	if opts.Duration <= 0 {
+		return LoadTestReport{}, fmt.Errorf("duration must be positive (got %s)", opts.Duration)
+	}
+	if cfg.Endpoint == config.EndpointEmbeddings {
+		return LoadTestReport{}, fmt.Errorf("loadtest measures generation; endpoint %q is not supported", cfg.Endpoint)
+	}
+
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		return LoadTestReport{}, err
+	}
+	prompts, err = renderPrompts(prompts, modelName, url, cfg.Vars)
+	if err != nil {
+		return LoadTestReport{}, err
+	}
+	capped := *cfg
+	applyMaxTokens(&capped)
+	cfg = &capped
+	var inferCfg map[string]interface{}
+	if len(cfg.InferConfigs) > 0 {
+		inferCfg = cfg.InferConfigs[0]
+	}
+
+	// One attempt per request: retries would mask failures and skew latency
+	ltCfg := *cfg
+	ltCfg.MaxRetries = 1
+	e := New(&ltCfg)
+
+	output.Logger.Info("Starting load test", "model", modelName, "url", url, "concurrency", opts.Concurrency, "duration", opts.Duration, "config", inferCfg)
+
+	var (
+		mu      sync.Mutex
+		results []model.Result
+		errs    []error
+		next    int // Prompt rotation
+	)
+	start := time.Now()
+	stopAt := start.Add(opts.Duration)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(stopAt) && ctx.Err() == nil {
+				mu.Lock()
+				prompt := prompts[next%len(prompts)]
+				next++
+				mu.Unlock()
+
+				res, err := runInference(ctx, e, &ltCfg, url, modelName, prompt, inferCfg)
+				if ctx.Err() != nil {
+					return // Interrupted: the request says nothing about the server
+				}
+
+				mu.Lock()
+				if err != nil {
+					output.Logger.Debug("Load test request failed", "model", modelName, "error", err)
+					errs = append(errs, err)
+				} else {
+					results = append(results, res)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	report := LoadTestReport{
+		Model:       modelName,
+		URL:         url,
+		Config:      inferCfg,
+		Concurrency: opts.Concurrency,
+		Wall:        time.Since(start),
+		Requests:    len(results) + len(errs),
+		Errors:      len(errs),
+		ErrorKinds:  make(map[string]int),
+	}
+	for _, err := range errs {
+		kind, _ := errorDetails(err)
+		if kind == "" {
+			kind = "other"
+		}
+		report.ErrorKinds[kind]++
+	}
+
+	tps := make([]float64, 0, len(results))
+	latencies := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		report.Tokens += r.TokensGenerated
+		tps = append(tps, r.TokensPerSecond)
+		latencies = append(latencies, r.Duration)
+	}
+	if report.Wall > 0 {
+		report.TokensPerSecond = float64(report.Tokens) / report.Wall.Seconds()
+	}
+	report.PerRequestTPS = model.ComputeStats(tps)
+	report.Latency = model.NewLatencyStats(latencies)
+	return report, ctx.Err()
+}
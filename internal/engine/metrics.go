@@ -0,0 +1,133 @@
+/*
+PURPOSE:
+  Exposes live Prometheus metrics while a fleet cruise is running.
+  Lets long runs be watched in Grafana instead of tailing logs.
+
+REQUIREMENTS:
+  User-specified:
+  - Optional --metrics-addr (e.g. ":9090") serving /metrics via promhttp.
+  - forest_runner_model_tokens_per_second{model,url}
+  - forest_runner_vram_percentage{model,url}
+  - forest_runner_runs_total, forest_runner_errors_total
+  - Updated after each result; server shut down when Run returns.
+
+  Implementation-discovered:
+  - Implemented as an output.ResultWriter so every result that reaches the file
+    writers (including pull failures) updates the metrics through writeResult.
+  - A private registry keeps the endpoint to forest_runner_* series only.
+  - The listener is opened up front so a busy port fails the run before any work.
+  - Gauges hold the latest successful value; repeats and configs overwrite it.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error if the address cannot be bound.
+  - Serve errors after startup are logged, not fatal.
+
+IMPLEMENTATION RULES:
+  - Thread-safe (Prometheus collectors are).
+
+USAGE:
+  m, err := startMetrics(":9090")
+  writers = append(writers, m)
+  defer m.Close() // stops the HTTP server
+
+SELF-HEALING INSTRUCTIONS:
+  - "address already in use": pick another --metrics-addr port.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/output/writer.go
+
+MAINTENANCE:
+  - Keep metric names stable; dashboards depend on them.
+*/
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsShutdownTimeout bounds how long Close waits for in-flight scrapes.
+const metricsShutdownTimeout = 5 * time.Second
+
+// runMetrics records results as Prometheus series and serves them over HTTP.
+type runMetrics struct {
+	tokensPerSecond *prometheus.GaugeVec
+	vramPercentage  *prometheus.GaugeVec
+	runsTotal       prometheus.Counter
+	errorsTotal     prometheus.Counter
+	server          *http.Server
+}
+
+// startMetrics registers the run metrics and starts serving /metrics on addr.
+func startMetrics(addr string) (*runMetrics, error) {
+	m := &runMetrics{
+		tokensPerSecond: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "forest_runner_model_tokens_per_second",
+			Help: "Tokens per second of the latest successful run for a model on a backend.",
+		}, []string{"model", "url"}),
+		vramPercentage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "forest_runner_vram_percentage",
+			Help: "Share of the model resident in VRAM during its latest successful run.",
+		}, []string{"model", "url"}),
+		runsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "forest_runner_runs_total",
+			Help: "Benchmark results recorded, including failures.",
+		}),
+		errorsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "forest_runner_errors_total",
+			Help: "Benchmark results recorded with an error.",
+		}),
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.tokensPerSecond, m.vramPercentage, m.runsTotal, m.errorsTotal)
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	m.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := m.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.Logger.Error("Metrics server stopped", "addr", addr, "error", err)
+		}
+	}()
+	return m, nil
+}
+
+// Write updates the metrics from a single result.
+func (m *runMetrics) Write(r model.Result) error {
+	m.runsTotal.Inc()
+	if r.Error != "" {
+		m.errorsTotal.Inc()
+		return nil
+	}
+	m.tokensPerSecond.WithLabelValues(r.Model, r.URL).Set(r.TokensPerSecond)
+	m.vramPercentage.WithLabelValues(r.Model, r.URL).Set(r.VRAMPercentage)
+	return nil
+}
+
+// Close stops the metrics server, letting in-flight scrapes finish.
+func (m *runMetrics) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsShutdownTimeout)
+	defer cancel()
+	return m.server.Shutdown(ctx)
+}
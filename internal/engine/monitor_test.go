@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+)
+
+// verifyNoLeaks fails the test if goroutines started after it was called are
+// still running at cleanup, once e's idle keep-alive connections are closed.
+func verifyNoLeaks(t *testing.T, e *Engine) {
+	t.Helper()
+	ignore := goleak.IgnoreCurrent()
+	t.Cleanup(func() {
+		e.Client.CloseIdleConnections()
+		goleak.VerifyNone(t, ignore)
+	})
+}
+
+func TestStopMonitorEndsPolling(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b")
+	cfg := testConfig(t, srv.URL, "llama3:8b")
+	cfg.GPUOnly = true
+	e := New(cfg)
+	verifyNoLeaks(t, e)
+
+	abort, stop := e.startMonitor(context.Background(), srv.URL, "llama3:8b", func() {})
+	for srv.hitCount("/api/ps") < 2 {
+		time.Sleep(cfg.MonitorInterval)
+	}
+	stop()
+
+	// A poll cancelled by stop may still reach the server just after it returns
+	time.Sleep(cfg.MonitorInterval)
+	polls := srv.hitCount("/api/ps")
+	time.Sleep(3 * cfg.MonitorInterval)
+	if got := srv.hitCount("/api/ps"); got != polls {
+		t.Fatalf("/api/ps polled %d more times after stop", got-polls)
+	}
+	select {
+	case err := <-abort:
+		t.Fatalf("unexpected abort: %v", err)
+	default:
+	}
+}
+
+func TestMonitorAbortStopsCleanly(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b")
+	srv.running = []runningModel{{Name: "llama3:8b", Size: 8 << 30, SizeVRAM: 4 << 30}}
+	cfg := testConfig(t, srv.URL, "llama3:8b")
+	cfg.GPUOnly = true
+	e := New(cfg)
+	verifyNoLeaks(t, e)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	abort, stop := e.startMonitor(ctx, srv.URL, "llama3:8b", cancel)
+	defer stop()
+
+	select {
+	case err := <-abort:
+		if kind, _ := errorDetails(err); kind != ErrorKindPlacement {
+			t.Fatalf("abort = %v, want a placement error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor did not abort a model split across CPU and GPU")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("abort did not cancel the request")
+	}
+}
+
+func TestStreamInferenceLeavesNoMonitor(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b")
+	cfg := testConfig(t, srv.URL, "llama3:8b")
+	cfg.GPUOnly = true
+	e := New(cfg)
+	verifyNoLeaks(t, e)
+
+	srv.failNext("/api/generate", 1)
+	if _, err := e.StreamInference(context.Background(), srv.URL, "llama3:8b", "hi", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
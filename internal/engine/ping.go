@@ -0,0 +1,111 @@
+/*
+PURPOSE:
+  Lightweight reachability check for backend URLs (forest-runner ping).
+  One cheap GET per host with a short timeout; no model is listed or loaded.
+
+REQUIREMENTS:
+  User-specified:
+  - Hit /api/version (or /api/tags) on each URL with a short timeout.
+  - Report reachable/unreachable with latency.
+  - Reuse the Engine's HTTP client with a per-call shorter timeout.
+
+  Implementation-discovered:
+  - Very old Ollama builds lack /api/version (404), so /api/tags is the fallback.
+  - OpenAI-compatible backends are probed via /v1/models.
+  - The timeout is applied through the request context, so the shared client
+    (transport, TLS, headers) is reused unchanged.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/ping.go
+
+ERROR HANDLING:
+  - Returns error on connection failure, timeout or a non-2xx status.
+
+IMPLEMENTATION RULES:
+  - Latency covers the whole request, including connection setup.
+
+USAGE:
+  version, latency, err := e.Ping(ctx, "http://localhost:11434", 5*time.Second)
+
+SELF-HEALING INSTRUCTIONS:
+  - "context deadline exceeded": host is up but slow, or a firewall drops packets; raise --timeout.
+
+RELATED FILES:
+  - internal/engine/client.go
+  - internal/cli/ping.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+)
+
+// Ping checks that baseURL answers within timeout and returns the server
+// version when the backend reports one.
+func (e *Engine) Ping(ctx context.Context, baseURL string, timeout time.Duration) (string, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if e.Config.ProtocolFor(baseURL) == config.ProtocolOpenAI {
+		latency, _, err := e.probe(ctx, baseURL, "/v1/models")
+		return "", latency, err
+	}
+
+	latency, body, err := e.probe(ctx, baseURL, "/api/version")
+	if errors.Is(err, errNotFound) {
+		latency, _, err = e.probe(ctx, baseURL, "/api/tags")
+		return "", latency, err
+	}
+	if err != nil {
+		return "", latency, err
+	}
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	json.Unmarshal(body, &version) // A reply is enough; the version is a bonus
+	return version.Version, latency, nil
+}
+
+// errNotFound lets Ping fall back to another endpoint on a 404.
+var errNotFound = errors.New("bad status: 404 Not Found")
+
+// probe sends a GET and returns its latency and body. Non-2xx statuses are errors.
+func (e *Engine) probe(ctx context.Context, baseURL, path string) (time.Duration, []byte, error) {
+	req, err := e.newRequest(ctx, "GET", baseURL, path, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	start := time.Now()
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return time.Since(start), nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	latency := time.Since(start)
+	if err != nil {
+		return latency, nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return latency, nil, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return latency, nil, fmt.Errorf("bad status: %s", resp.Status)
+	}
+	return latency, body, nil
+}
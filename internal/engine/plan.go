@@ -0,0 +1,191 @@
+/*
+PURPOSE:
+  Previews a run (--dry-run): discovers and filters models on every URL and
+  prints the url x model x prompt x config matrix that Run would execute.
+
+REQUIREMENTS:
+  User-specified:
+  - Perform discovery and filtering, then print the full matrix with a count.
+  - No inference calls and no files written.
+  - Respect --include/--exclude/--models so the preview is accurate.
+
+  Implementation-discovered:
+  - Shares selectModels, orderModels, capModels and pendingRuns with Run, so
+    the preview follows the same filters, model_order, shuffle seed,
+    max_models cap and --resume skips.
+  - Explicit models missing from an Ollama host are flagged: "pull" with
+    auto_pull, otherwise "not installed" (they would fail at run time).
+  - With shuffle and seed 0 the real order is only fixed when Run picks a seed,
+    so the preview says so instead of showing an order that won't happen.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/run.go (--dry-run)
+
+ERROR HANDLING:
+  - Returns error for invalid modes, filters, prompts or resume file, like Run.
+  - A URL that can't be queried is reported in the listing; other URLs continue.
+
+IMPLEMENTATION RULES:
+  - Read-only: GET /api/tags only (and /v1/models for OpenAI backends).
+  - The listing is buffered and printed after discovery, since the logger
+    also writes to stdout.
+
+USAGE:
+  err := engine.Plan(ctx, cfg, os.Stdout)
+
+SELF-HEALING INSTRUCTIONS:
+  - "0 runs planned": check include/exclude filters and the models list.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/cli/run.go
+
+MAINTENANCE:
+  - Keep in step with runForURL when the per-URL pipeline changes.
+*/
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+)
+
+// Plan prints the work Run would do for cfg without running inference or writing files.
+func Plan(ctx context.Context, cfg *config.Config, out io.Writer) error {
+	if err := cfg.NormalizeURLs(); err != nil {
+		return err
+	}
+	if err := checkConfig(cfg); err != nil {
+		return err
+	}
+	applyCtxSweep(cfg)
+	applyMaxTokens(cfg)
+	filters, err := newModelFilters(cfg)
+	if err != nil {
+		return err
+	}
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		return err
+	}
+	done, err := loadResume(cfg.Resume)
+	if err != nil {
+		return err
+	}
+
+	repeat := max(cfg.Repeat, 1)
+	e := New(cfg)
+
+	// Discovery logs share stdout; buffer the listing so it prints in one piece
+	var w strings.Builder
+
+	fmt.Fprintln(&w, "Dry run: no inference requests are sent and no files are written.")
+	if cfg.Shuffle && cfg.Seed == 0 {
+		fmt.Fprintln(&w, "Shuffle is on without a seed; the order below is not the order the run will use.")
+	}
+
+	var totalModels, totalRuns int
+	for _, url := range cfg.URLs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		fmt.Fprintf(&w, "\n%s (%s)\n", url, cfg.ProtocolFor(url))
+
+		selected, err := selectModels(ctx, e, cfg, url, filters)
+		if err != nil {
+			fmt.Fprintf(&w, "  discovery failed: %v\n", err)
+			continue
+		}
+		notes := missingModelNotes(ctx, e, cfg, url, selected)
+		selected = orderModels(ctx, e, cfg, url, selected)
+		if cfg.Shuffle {
+			shuffleFor(cfg, selected, url)
+		}
+		if capped := capModels(cfg, selected); len(capped) < len(selected) {
+			fmt.Fprintf(&w, "  %d of %d models (max_models)\n", len(capped), len(selected))
+			selected = capped
+		}
+		if len(selected) == 0 {
+			fmt.Fprintln(&w, "  no models selected")
+			continue
+		}
+
+		for _, modelName := range selected {
+			pending := pendingRuns(cfg, url, modelName, prompts, done)
+			label := modelName
+			if note := notes[modelName]; note != "" {
+				label += " (" + note + ")"
+			}
+			if len(pending) == 0 {
+				fmt.Fprintf(&w, "  %s: already benchmarked, skipped\n", label)
+				continue
+			}
+			fmt.Fprintf(&w, "  %s\n", label)
+			if cfg.HealthCheckOnly && cfg.Endpoint != config.EndpointEmbeddings {
+				desc := "stream test (health check only)"
+				if name := pending[0].prompt.Name; name != "" {
+					desc = "[" + name + "] " + desc
+				}
+				fmt.Fprintf(&w, "    %s\n", desc)
+				totalModels++
+				totalRuns++
+				continue
+			}
+			for _, run := range pending {
+				fmt.Fprintf(&w, "    %s\n", describeRun(cfg, run))
+			}
+			totalModels++
+			totalRuns += len(pending)
+		}
+	}
+
+	fmt.Fprintf(&w, "\nPlanned: %d URL(s), %d model(s), %d run(s)", len(cfg.URLs), totalModels, totalRuns)
+	if repeat > 1 {
+		fmt.Fprintf(&w, " x %d repeats = %d inference requests", repeat, totalRuns*repeat)
+	}
+	fmt.Fprintln(&w)
+	_, err = io.WriteString(out, w.String())
+	return err
+}
+
+// missingModelNotes flags explicitly requested models an Ollama host doesn't have.
+func missingModelNotes(ctx context.Context, e *Engine, cfg *config.Config, url string, selected []string) map[string]string {
+	notes := make(map[string]string)
+	if len(cfg.Models) == 0 || cfg.ProtocolFor(url) != config.ProtocolOllama {
+		return notes
+	}
+	installed, err := e.GetModels(ctx, url)
+	if err != nil {
+		return notes
+	}
+	for _, modelName := range selected {
+		if hasModel(installed, modelName) {
+			continue
+		}
+		if cfg.AutoPull {
+			notes[modelName] = "pull"
+		} else {
+			notes[modelName] = "not installed"
+		}
+	}
+	return notes
+}
+
+// describeRun renders one planned run as "[prompt] {config}".
+func describeRun(cfg *config.Config, run pendingRun) string {
+	desc := "embedding"
+	if cfg.Endpoint != config.EndpointEmbeddings {
+		b, _ := json.Marshal(run.inferCfg)
+		desc = string(b)
+	}
+	if run.prompt.Name != "" {
+		desc = "[" + run.prompt.Name + "] " + desc
+	}
+	return desc
+}
@@ -0,0 +1,177 @@
+/*
+PURPOSE:
+  Builds the list of prompts a run benchmarks each model against.
+  A single config/flag prompt, or every .txt/.md file in prompt_dir.
+  With prompt_templates (or any vars) prompts are text/template sources
+  rendered per model; otherwise they are sent as written.
+
+REQUIREMENTS:
+  User-specified:
+  - --prompt-dir benchmarks each model against each prompt file.
+  - Rows are labelled with the prompt's file name (Result.PromptName).
+  - {{.Model}}, {{.URL}} and every key in cfg.Vars are available to prompts.
+  - A reference to an undefined variable fails the run with a clear error.
+
+  Implementation-discovered:
+  - Files are sorted by name so runs are reproducible.
+  - The single-prompt case keeps an empty name so existing result files,
+    --resume and compare keep working unchanged.
+  - Templates are parsed and test-rendered in Run, so a typo fails before any
+    model is loaded rather than once per model mid-run.
+  - missingkey=error only applies to maps, so template data is a flat map.
+  - Templating is opt-in: prompts and prompt files often contain literal
+    {{ }} (Jinja, Helm, Go code samples), which must not fail the run.
+  - Configured images are attached here (images.go), after parsing, so every
+    caller of loadPrompts (run, loadtest) sends them.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run, runForURL)
+
+ERROR HANDLING:
+  - Returns error if prompt_dir can't be read, a file can't be read, or it
+    contains no prompt files.
+  - Returns error if a prompt is not a valid template or uses an undefined variable.
+
+IMPLEMENTATION RULES:
+  - Non-recursive; subdirectories and other extensions are ignored.
+
+USAGE:
+  prompts, err := loadPrompts(cfg)
+  rendered, err := renderPrompts(prompts, modelName, url, cfg.Vars)
+
+SELF-HEALING INSTRUCTIONS:
+  - "map has no entry for key": define the variable under vars: or pass --var key=value.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/engine/images.go
+  - internal/config/config.go
+
+MAINTENANCE:
+  - Extend promptExtensions to accept more file types.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+)
+
+// promptExtensions are the file types read from prompt_dir.
+var promptExtensions = map[string]bool{".txt": true, ".md": true}
+
+// namedPrompt is one prompt to benchmark. Name is empty for the single-prompt case.
+// Text is the raw template until renderPrompts fills it in for a model.
+type namedPrompt struct {
+	Name   string
+	Text   string
+	Images []string // Base64-encoded images sent with the prompt (images config)
+	tmpl   *template.Template
+
+	imageBytes int64 // Total size of Images' source files
+}
+
+// loadPrompts returns the prompts for this run: every prompt file in
+// cfg.PromptDir if set, otherwise cfg.Prompt alone.
+func loadPrompts(cfg *config.Config) ([]namedPrompt, error) {
+	if cfg.PromptDir == "" {
+		prompts, err := parsePrompts(cfg, []namedPrompt{{Text: cfg.Prompt}})
+		if err != nil {
+			return nil, err
+		}
+		return withImages(cfg, prompts)
+	}
+
+	entries, err := os.ReadDir(cfg.PromptDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt_dir %s: %w", cfg.PromptDir, err)
+	}
+
+	var prompts []namedPrompt
+	for _, entry := range entries {
+		if entry.IsDir() || !promptExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(cfg.PromptDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt file: %w", err)
+		}
+		prompts = append(prompts, namedPrompt{Name: entry.Name(), Text: string(data)})
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("prompt_dir %s contains no .txt or .md files", cfg.PromptDir)
+	}
+
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	prompts, err = parsePrompts(cfg, prompts)
+	if err != nil {
+		return nil, err
+	}
+	return withImages(cfg, prompts)
+}
+
+// parsePrompts compiles each prompt as a template and renders it once with
+// placeholder values, so undefined variables are reported before the run starts.
+// Without templating the prompts are returned as written.
+func parsePrompts(cfg *config.Config, prompts []namedPrompt) ([]namedPrompt, error) {
+	if !cfg.TemplatePrompts() {
+		return prompts, nil
+	}
+	for i := range prompts {
+		label := prompts[i].Name
+		if label == "" {
+			label = "prompt"
+		}
+		tmpl, err := template.New(label).Option("missingkey=error").Parse(prompts[i].Text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template in %s: %w", label, err)
+		}
+		prompts[i].tmpl = tmpl
+		if _, err := prompts[i].render("", "", cfg.Vars); err != nil {
+			return nil, err
+		}
+	}
+	return prompts, nil
+}
+
+// render executes the prompt template for one model on one backend.
+// Model and URL are built in; user vars cannot shadow them (config validation rejects that).
+// An untemplated prompt is returned unchanged.
+func (p namedPrompt) render(modelName, url string, vars map[string]string) (namedPrompt, error) {
+	if p.tmpl == nil {
+		return p, nil
+	}
+	data := make(map[string]string, len(vars)+2)
+	for k, v := range vars {
+		data[k] = v
+	}
+	data["Model"] = modelName
+	data["URL"] = url
+
+	var sb strings.Builder
+	if err := p.tmpl.Execute(&sb, data); err != nil {
+		return p, fmt.Errorf("failed to render %s (define missing variables under vars: or with --var): %w", p.tmpl.Name(), err)
+	}
+	p.Text = sb.String()
+	return p, nil
+}
+
+// renderPrompts renders every prompt for a single model.
+func renderPrompts(prompts []namedPrompt, modelName, url string, vars map[string]string) ([]namedPrompt, error) {
+	rendered := make([]namedPrompt, len(prompts))
+	for i, p := range prompts {
+		r, err := p.render(modelName, url, vars)
+		if err != nil {
+			return nil, err
+		}
+		rendered[i] = r
+	}
+	return rendered, nil
+}
@@ -0,0 +1,74 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLiteralBracesRunVerbatim(t *testing.T) {
+	const prompt = "Explain this Helm snippet: image: {{ .Values.image }} and this Jinja: {{ user.name }}"
+	srv := newFakeOllama(t, "tiny:1b")
+	cfg := testConfig(t, srv.URL, "tiny:1b")
+	cfg.Prompt = prompt
+
+	if err := Run(context.Background(), cfg); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	reqs := srv.requests("/api/generate")
+	if len(reqs) == 0 {
+		t.Fatal("no generate requests reached the server")
+	}
+	for _, r := range reqs {
+		if r["prompt"] != prompt {
+			t.Errorf("prompt = %q, want it sent as written", r["prompt"])
+		}
+	}
+}
+
+func TestPromptTemplates(t *testing.T) {
+	cfg := testConfig(t, "http://localhost:11434")
+	cfg.Prompt = "Write {{.lang}} for {{.Model}}"
+	cfg.Vars = map[string]string{"lang": "Go"}
+
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		t.Fatalf("loadPrompts: %v", err)
+	}
+	rendered, err := renderPrompts(prompts, "tiny:1b", cfg.URLs[0], cfg.Vars)
+	if err != nil {
+		t.Fatalf("renderPrompts: %v", err)
+	}
+	if got, want := rendered[0].Text, "Write Go for tiny:1b"; got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+
+	cfg.Prompt = "Write {{.missing}}"
+	if _, err := loadPrompts(cfg); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("undefined variable: err = %v, want a missing-variable error", err)
+	}
+}
+
+func TestPromptTemplatesOptIn(t *testing.T) {
+	cfg := testConfig(t, "http://localhost:11434")
+	cfg.Prompt = "Hello from {{.Model}}"
+
+	prompts, err := loadPrompts(cfg)
+	if err != nil {
+		t.Fatalf("loadPrompts: %v", err)
+	}
+	rendered, _ := renderPrompts(prompts, "tiny:1b", cfg.URLs[0], nil)
+	if rendered[0].Text != cfg.Prompt {
+		t.Errorf("without prompt_templates got %q, want it unchanged", rendered[0].Text)
+	}
+
+	cfg.PromptTemplates = true
+	prompts, err = loadPrompts(cfg)
+	if err != nil {
+		t.Fatalf("loadPrompts: %v", err)
+	}
+	rendered, _ = renderPrompts(prompts, "tiny:1b", cfg.URLs[0], nil)
+	if got, want := rendered[0].Text, "Hello from tiny:1b"; got != want {
+		t.Errorf("with prompt_templates got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// stubProxy is a forwarding HTTP proxy that records the URLs it was asked for.
+type stubProxy struct {
+	*httptest.Server
+	mu   sync.Mutex
+	seen []string
+	auth []string // Proxy-Authorization headers received
+}
+
+func newStubProxy(t *testing.T) *stubProxy {
+	t.Helper()
+	p := &stubProxy{}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.seen = append(p.seen, r.URL.String())
+		p.auth = append(p.auth, r.Header.Get("Proxy-Authorization"))
+		p.mu.Unlock()
+
+		out := r.Clone(r.Context())
+		out.RequestURI = ""
+		out.Header.Del("Proxy-Authorization")
+		resp, err := http.DefaultTransport.RoundTrip(out)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func TestRequestsRouteThroughProxy(t *testing.T) {
+	srv := newFakeOllama(t, "tiny:1b", "llama3:8b")
+	proxy := newStubProxy(t)
+	cfg := testConfig(t, srv.URL)
+	cfg.Proxy = "http://bob:s3cret@" + proxy.Listener.Addr().String()
+
+	models, err := New(cfg).GetModels(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("GetModels through proxy: %v", err)
+	}
+	if len(models) != 2 {
+		t.Errorf("got %d models, want 2", len(models))
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if len(proxy.seen) != 1 || proxy.seen[0] != srv.URL+"/api/tags" {
+		t.Fatalf("proxy saw %v, want one request for %s/api/tags", proxy.seen, srv.URL)
+	}
+	if proxy.auth[0] == "" {
+		t.Error("proxy credentials were not sent as Proxy-Authorization")
+	}
+}
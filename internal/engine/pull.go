@@ -0,0 +1,126 @@
+/*
+PURPOSE:
+  Downloads missing models onto a backend via /api/pull before benchmarking.
+
+REQUIREMENTS:
+  User-specified:
+  - Pull requested models that GetModels doesn't report; skip ones already present.
+  - Report download progress through the logger.
+  - A failed pull fails that model only, not the whole run.
+
+  Implementation-discovered:
+  - /api/pull streams NDJSON status lines ({"status","total","completed"}) and
+    reports failures in-band as {"error": "..."} with a 200 status.
+  - Multi-GB downloads far exceed the inference client timeout, so pulls use a
+    client with no overall timeout (the caller's ctx still cancels them).
+  - Ollama lists untagged models as "<name>:latest".
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (when auto_pull is enabled)
+
+ERROR HANDLING:
+  - Returns error on connection failure, non-200 status, in-band error, or a
+    stream that ends without "success".
+
+IMPLEMENTATION RULES:
+  - Log progress at most every 10% per layer so the log stays readable.
+
+USAGE:
+  err := e.PullModel(ctx, "http://localhost:11434", "qwen2.5:7b")
+
+SELF-HEALING INSTRUCTIONS:
+  - "file does not exist": the model name/tag doesn't exist in the registry.
+
+RELATED FILES:
+  - internal/engine/client.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// PullModel downloads a model onto an Ollama host, logging progress as it streams.
+func (e *Engine) PullModel(ctx context.Context, baseURL, modelName string) error {
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"model":  modelName,
+		"stream": true,
+	})
+
+	req, err := e.newRequest(ctx, "POST", baseURL, "/api/pull", reqBody)
+	if err != nil {
+		return err
+	}
+
+	// Same transport (header timeout, TLS), but no overall deadline for large downloads
+	client := &http.Client{Transport: e.Client.Transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return withKind(ErrorKindNetwork, 0, fmt.Errorf("Network/Connection Error: %w", err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return statusError("Ollama", resp, body)
+	}
+
+	lastPct := make(map[string]int64) // per layer digest
+	lastStatus := ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var chunk struct {
+			Status    string `json:"status"`
+			Digest    string `json:"digest"`
+			Total     int64  `json:"total"`
+			Completed int64  `json:"completed"`
+			Error     string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			continue // Garbage resilience, same as the inference stream
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("%w: %s", errAPI, chunk.Error)
+		}
+		if chunk.Status == "success" {
+			return nil
+		}
+
+		if chunk.Total > 0 {
+			pct := chunk.Completed * 100 / chunk.Total
+			if prev, seen := lastPct[chunk.Digest]; !seen || pct >= prev+10 || (pct == 100 && prev != 100) {
+				lastPct[chunk.Digest] = pct
+				output.Logger.Info("Pulling model", "model", modelName, "url", baseURL, "status", chunk.Status, "progress", fmt.Sprintf("%d%%", pct))
+			}
+		} else if chunk.Status != lastStatus {
+			output.Logger.Info("Pulling model", "model", modelName, "url", baseURL, "status", chunk.Status)
+		}
+		lastStatus = chunk.Status
+	}
+	if err := scanner.Err(); err != nil {
+		return withKind(ErrorKindNetwork, 0, fmt.Errorf("Network/Connection Error: %w", err))
+	}
+	return withKind(ErrorKindAPI, resp.StatusCode, fmt.Errorf("pull stream ended without success"))
+}
+
+// hasModel reports whether name is in the list of installed models,
+// treating an untagged name as ":latest" the way Ollama does.
+func hasModel(installed []string, name string) bool {
+	for _, m := range installed {
+		if withTag(m) == withTag(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+/*
+PURPOSE:
+  Resume support for interrupted fleet runs.
+  Reads a prior JSON Lines result file and records which work already succeeded.
+
+REQUIREMENTS:
+  User-specified:
+  - Skip (url, model, prompt, config) tuples that already succeeded.
+  - Re-run previously failed tuples.
+
+  Implementation-discovered:
+  - Config maps are canonicalized via encoding/json, which sorts map keys.
+  - Numbers round-trip through JSON as float64 but marshal identically (2048 == 2048).
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go
+  - Consumes: NDJSON written by internal/output.JSONWriter
+
+ERROR HANDLING:
+  - Returns error if the resume file cannot be opened.
+  - Invalid lines are skipped by output.ReadResults.
+
+IMPLEMENTATION RULES:
+  - Read-only; never modifies the resume file.
+
+USAGE:
+  done, err := loadResume("model_results.json")
+  if done.has(url, model, promptName, cfg) { ... }
+
+SELF-HEALING INSTRUCTIONS:
+  - If nothing is skipped, check that URLs match exactly (including scheme/port).
+
+RELATED FILES:
+  - internal/output/json.go
+  - internal/output/reader.go
+
+MAINTENANCE:
+  - Update key() if the identity of a benchmark tuple changes.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// resumeSet holds the keys of (url, model, config) tuples that already succeeded.
+type resumeSet map[string]bool
+
+// configKey canonicalizes an inference config to sorted-key JSON.
+func configKey(cfg map[string]interface{}) string {
+	data, _ := json.Marshal(cfg)
+	return string(data)
+}
+
+func (r resumeSet) key(url, modelName, promptName string, cfg map[string]interface{}) string {
+	return url + "\x00" + modelName + "\x00" + promptName + "\x00" + configKey(cfg)
+}
+
+// has reports whether the tuple succeeded in the resumed run. A nil set never matches.
+func (r resumeSet) has(url, modelName, promptName string, cfg map[string]interface{}) bool {
+	return r[r.key(url, modelName, promptName, cfg)]
+}
+
+// loadResume reads a JSON Lines result file and collects every successful tuple.
+// An empty path returns a nil set (nothing to skip).
+func loadResume(path string) (resumeSet, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	results, err := output.ReadResults(path)
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(resumeSet)
+	for _, r := range results {
+		if r.Error == "" {
+			done[done.key(r.URL, r.Model, r.PromptName, r.Config)] = true
+		}
+	}
+	return done, nil
+}
@@ -10,6 +10,24 @@ REQUIREMENTS:
 
   Implementation-discovered:
   - Needs to report progress to CLI.
+  - Shuffle seeds a fresh generator from (seed, url[, model]) rather than sharing
+    one, so concurrent URL/model workers can't change each other's order.
+  - model_order=size uses the size field /api/tags already returns, so no
+    per-model /api/show round trip is needed.
+  - verify_determinism compares response hashes of a second, unrecorded
+    request; it shows up in JSON results only, so the CSV/SQLite column sets
+    (and files appended to across runs) stay unchanged.
+  - stream_benchmark records the health-check stream as the first config's
+    iteration 1; it is the generate endpoint's streaming twin, so chat and
+    embeddings runs keep the separate request.
+  - max_tokens is applied once, before the config snapshot, so the recorded
+    inference configs show the num_predict that was actually sent. Whether a
+    run hit the cap comes from the backend's done_reason ("length").
+  - ctx_sweep expands inference_configs before max_tokens; the prompt is
+    padded per pending run (ctxsweep.go) since the fill depends on num_ctx.
+  - run_id is generated before the config snapshot, so config.used.yaml
+    (which also names the tool version and commit) ties back to the rows;
+    writeResult stamps it and the labels on every result.
 
 ARCHITECTURE INTEGRATION:
   - Called by: internal/cli
@@ -35,67 +53,243 @@ RELATED FILES:
 
 MAINTENANCE:
   - Update iteration logic if parallelism is introduced.
+  - New output formats: implement output.ResultWriter and add a row to the formats table in Run.
 */
 
 package engine
 
 import (
+	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
 	"github.com/daryltucker/forest-runner/internal/output"
+	"github.com/google/uuid"
 )
 
-// nextAvailablePath returns the original path if it doesn't exist,
-// otherwise appends .1, .2, etc. until an available path is found.
-func nextAvailablePath(path string) string {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return path
+// ToolVersion is recorded in config snapshots and run metadata.
+// The CLI sets it from its build information.
+var ToolVersion = "dev"
+
+// ErrInterrupted is returned by Run when ctx is cancelled (e.g. SIGINT) before the
+// suite completes. Results written up to that point are flushed and closed.
+var ErrInterrupted = errors.New("run interrupted")
+
+// ErrFailFast is returned by Run when fail_fast_run stopped it at the first failed result.
+var ErrFailFast = errors.New("run aborted on the first failure (fail_fast_run)")
+
+// Run executes the full benchmark suite.
+// Cancelling ctx stops new work, aborts in-flight requests without recording
+// them, and closes all output files before returning ErrInterrupted.
+func Run(ctx context.Context, cfg *config.Config) error {
+	started := time.Now()
+	if err := cfg.NormalizeURLs(); err != nil {
+		return err
 	}
 
-	for i := 1; ; i++ {
-		newPath := fmt.Sprintf("%s.%d", path, i)
-		if _, err := os.Stat(newPath); os.IsNotExist(err) {
-			return newPath
-		}
+	// Fix the seed up front so the config snapshot can reproduce this order
+	if cfg.Shuffle && cfg.Seed == 0 {
+		cfg.Seed = rand.Int64()
+		output.Logger.Info("Shuffling with random seed", "seed", cfg.Seed)
 	}
-}
 
-// Run executes the full benchmark suite.
-func Run(cfg *config.Config) error {
-	e := New(cfg)
+	// One ID per invocation tags every row; the snapshot records it
+	if cfg.RunID == "" {
+		cfg.RunID = uuid.NewString()
+	}
+	output.Logger.Info("Starting run", "run_id", cfg.RunID, "labels", cfg.Labels)
 
-	// Ensure output directory exists
-	if err := os.MkdirAll(cfg.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory %s: %w", cfg.OutputDir, err)
+	if err := checkConfig(cfg); err != nil {
+		return err
 	}
+	applyCtxSweep(cfg)
+	applyMaxTokens(cfg)
 
-	// Setup Outputs with Versioning
-	csvPath := nextAvailablePath(filepath.Join(cfg.OutputDir, cfg.OutputFile))
-	csvWriter, err := output.NewCSVWriter(csvPath)
+	if cfg.Warmup && cfg.KeepAlive == "0" {
+		output.Logger.Warn("Warmup disabled: keep_alive=0 unloads the model before the measured run")
+	}
+
+	// Compile model filters up front so a bad pattern fails before any work starts
+	filters, err := newModelFilters(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Read prompt files up front so a bad prompt_dir fails before any work starts
+	prompts, err := loadPrompts(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to init CSV writer at %s: %w", csvPath, err)
+		return err
+	}
+	if cfg.PromptDir != "" {
+		output.Logger.Info("Loaded prompts", "dir", cfg.PromptDir, "count", len(prompts))
+	}
+
+	// Resume: collect tuples that already succeeded in a previous run
+	done, err := loadResume(cfg.Resume)
+	if err != nil {
+		return err
+	}
+	if done != nil {
+		output.Logger.Info("Resuming from previous results", "path", cfg.Resume, "completed", len(done))
 	}
-	defer csvWriter.Close()
 
-	jsonPath := nextAvailablePath(filepath.Join(cfg.OutputDir, "model_results.json"))
-	jsonWriter, err := output.NewJSONWriter(jsonPath)
+	e := New(cfg)
+
+	csvComma, err := cfg.CSVComma()
 	if err != nil {
-		return fmt.Errorf("failed to init JSON writer at %s: %w", jsonPath, err)
+		return err
+	}
+	if cfg.CSVPrecision < 0 {
+		return fmt.Errorf("csv_precision must not be negative (got %d)", cfg.CSVPrecision)
+	}
+
+	// Bind the metrics port before reserving output files, so a port already
+	// in use fails the run without leaving empty numbered result files behind
+	var metrics *runMetrics
+	if cfg.MetricsAddr != "" {
+		metrics, err = startMetrics(cfg.MetricsAddr)
+		if err != nil {
+			return fmt.Errorf("failed to start metrics server on %s: %w", cfg.MetricsAddr, err)
+		}
+		defer metrics.Close()
+		output.Logger.Info("Serving Prometheus metrics", "addr", cfg.MetricsAddr, "path", "/metrics")
+	}
+	csvOpts := output.CSVOptions{Delimiter: csvComma, Precision: cfg.CSVPrecision}
+
+	// Setup Outputs with Versioning
+	// The sink gives all files the output_file stem and one run number.
+	// Every enabled writer's path is logged when the cruise ends.
+	// In csv_append mode the CSV skips versioning and grows in <stem>.csv.
+	// Writers that can take the sink's reserved handle get OpenFile; SQLite needs a path.
+	formats := []output.SinkFormat{
+		{Name: config.OutputCSV, Suffix: ".csv", OpenFile: func(f *os.File) (output.ResultWriter, error) {
+			return output.NewCSVFileWriter(f, csvOpts)
+		}},
+		{Name: config.OutputJSON, Suffix: ".json", OpenFile: output.NewJSONFileWriter},
+		{Name: config.OutputJSONArray, Suffix: output.JSONArraySuffix, OpenFile: output.NewJSONArrayFileWriter},
+		{Name: config.OutputSQLite, Suffix: ".db", Open: output.NewSQLiteWriter},
+		{Name: config.OutputMarkdown, Suffix: ".md", OpenFile: output.NewMarkdownFileWriter},
+		{Name: config.OutputHTML, Suffix: ".html", OpenFile: output.NewHTMLFileWriter},
+		{Name: config.OutputInflux, Suffix: ".lp", OpenFile: output.NewInfluxFileWriter},
+	}
+	if cfg.CSVAppend {
+		formats[0].OpenFile = nil
+		formats[0].Open = func(path string) (output.ResultWriter, error) {
+			return output.NewCSVWriterAppend(path, csvOpts)
+		}
+		formats[0].Unversioned = true
+	}
+	if cfg.JSONIndent {
+		formats[1].OpenFile = output.NewPrettyJSONFileWriter
+	}
+
+	stem := strings.TrimSuffix(cfg.OutputFile, filepath.Ext(cfg.OutputFile))
+	sink := output.NewSink(cfg.OutputDir, stem, cfg.RunDirLayout)
+	if !cfg.RunDirLayout {
+		sink.Reserve("config_used", output.ConfigUsedSuffix)
+	}
+	for _, f := range formats {
+		if cfg.HasOutput(f.Name) {
+			sink.Register(f)
+		}
+	}
+	if cfg.Repeat > 1 {
+		sink.Reserve("results_summary", output.SummarySuffix)
+	}
+	sink.Reserve("hosts", output.HostsSuffix)
+	if err := sink.Open(); err != nil {
+		return err
+	}
+	defer sink.Close()
+
+	// run_dir_layout bundles each run's files (and run.meta.json) in run-NNNN/
+	if cfg.RunDirLayout {
+		snapshot, err := cfg.Snapshot()
+		if err != nil {
+			return fmt.Errorf("failed to snapshot config: %w", err)
+		}
+		meta := output.RunMeta{Timestamp: time.Now(), Version: ToolVersion, RunID: cfg.RunID, Labels: cfg.Labels, URLs: cfg.Redacted().URLs, Config: snapshot}
+		if err := output.WriteRunMeta(sink.Dir(), meta); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output.RunMetaFile, err)
+		}
+		output.Logger.Info("Created run directory", "dir", sink.Dir())
+	}
+
+	// Record the fully resolved config (flags/env applied, secrets redacted) for reproducibility.
+	// A run directory holds exactly one run, so there it is simply config.used.yaml.
+	configPath := sink.Path(output.ConfigUsedSuffix)
+	paths := sink.Paths()
+	if cfg.RunDirLayout {
+		configPath = filepath.Join(sink.Dir(), "config.used.yaml")
+		paths = append([]any{"config_used", configPath}, paths...)
+	}
+	comment := fmt.Sprintf("Effective configuration for this run (header values redacted)\n%s, run %s, written %s", ToolVersion, cfg.RunID, time.Now().Format(time.RFC3339))
+	if err := cfg.WriteRedacted(configPath, comment); err != nil {
+		return fmt.Errorf("failed to write config snapshot %s: %w", configPath, err)
+	}
+
+	writers := sink.Writers()
+
+	// Per-token timing sidecars are opened lazily, one per model
+	if cfg.TokenTimings {
+		e.tokenLog = output.NewTokenLog(sink.Base())
+		defer e.tokenLog.Close()
+		paths = append(paths, "token_timings", sink.Base()+".<model>"+output.TokenLogSuffix)
+	}
+
+	// Repeat runs get an aggregated summary alongside the raw results
+	var summaryWriter *output.SummaryWriter
+	if cfg.Repeat > 1 {
+		summaryPath := sink.Path(output.SummarySuffix)
+		summaryWriter, err = output.NewSummaryWriter(summaryPath)
+		if err != nil {
+			return fmt.Errorf("failed to init summary writer at %s: %w", summaryPath, err)
+		}
+		defer summaryWriter.Close()
+	}
+
+	// End-of-run summary counts every result the files see
+	stats := newRunStats(started)
+	writers = append(writers, stats)
+
+	// Live metrics are fed by the same writeResult path as the files
+	if metrics != nil {
+		writers = append(writers, metrics)
+	}
+
+	// fail_fast_run: the first failed result cancels the run. Last in the list,
+	// so every other writer has recorded that result when it fires.
+	if cfg.FailFastRun {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		writers = append(writers, &failFastWriter{cancel: cancel})
 	}
-	defer jsonWriter.Close()
 
 	// Handle Concurrency
 	concurrency := cfg.Concurrency
 	if concurrency <= 0 {
+		if concurrency < 0 {
+			output.Logger.Warn("Negative concurrency, using 1", "concurrency", concurrency)
+		}
 		concurrency = 1
 	}
 	if concurrency > len(cfg.URLs) {
+		output.Logger.Warn("Concurrency exceeds number of backends; extra workers would idle", "concurrency", concurrency, "backends", len(cfg.URLs))
 		concurrency = len(cfg.URLs)
 	}
 
@@ -105,6 +299,15 @@ func Run(cfg *config.Config) error {
 	}
 	close(urlChan)
 
+	// Host info is written even for interrupted runs
+	hosts := output.NewHostRecorder()
+	hostsPath := sink.Path(output.HostsSuffix)
+	defer func() {
+		if err := hosts.WriteFile(hostsPath); err != nil {
+			output.Logger.Error("Failed to write host info", "path", hostsPath, "error", err)
+		}
+	}()
+
 	var wg sync.WaitGroup
 	output.Logger.Info("Starting Fleet Cruise", "backends", len(cfg.URLs), "concurrency", concurrency)
 
@@ -113,116 +316,858 @@ func Run(cfg *config.Config) error {
 		go func() {
 			defer wg.Done()
 			for url := range urlChan {
-				runForURL(e, cfg, url, csvWriter, jsonWriter)
+				if ctx.Err() != nil {
+					return
+				}
+				info, err := e.GetHostInfo(ctx, url)
+				if err != nil {
+					output.Logger.Warn("Could not collect host info", "url", url, "error", err)
+				} else if info.Version != "" {
+					output.Logger.Info("Host info", "url", url, "version", info.Version, "loaded_models", len(info.LoadedModels))
+				}
+				hosts.Record(info)
+				runForURL(ctx, e, cfg, url, filters, prompts, done, writers, summaryWriter)
 			}
 		}()
 	}
 
 	wg.Wait()
-	output.Logger.Info("Fleet Cruise Completed", "results_csv", csvPath, "results_json", jsonPath)
+	stats.log()
+	if errors.Is(context.Cause(ctx), ErrFailFast) {
+		output.Logger.Error("Fleet Cruise Aborted (fail_fast_run). Partial results saved.", paths...)
+		return ErrFailFast
+	}
+	if ctx.Err() != nil {
+		output.Logger.Warn("Fleet Cruise Interrupted. Partial results saved.", paths...)
+		return ErrInterrupted
+	}
+	output.Logger.Info("Fleet Cruise Completed", paths...)
+	return nil
+}
+
+// applyMaxTokens injects cfg.MaxTokens as "num_predict" into every inference
+// config that doesn't already set one. Configs are copied, not modified in place.
+func applyMaxTokens(cfg *config.Config) {
+	if cfg.MaxTokens <= 0 {
+		return
+	}
+	configs := make([]map[string]interface{}, len(cfg.InferConfigs))
+	for i, opts := range cfg.InferConfigs {
+		capped := make(map[string]interface{}, len(opts)+1)
+		for k, v := range opts {
+			capped[k] = v
+		}
+		if _, ok := capped["num_predict"]; !ok {
+			capped["num_predict"] = cfg.MaxTokens
+		}
+		configs[i] = capped
+	}
+	cfg.InferConfigs = configs
+}
+
+// checkConfig rejects unknown endpoint, protocol, model_order, fail_fast, output
+// and format values, and conflicting health check modes, before any work starts.
+// The checks are config.ModeProblems, shared with Validate. Shared by Run and Plan.
+func checkConfig(cfg *config.Config) error {
+	if problems := cfg.ModeProblems(); len(problems) > 0 {
+		msgs := make([]string, len(problems))
+		for i, p := range problems {
+			msgs[i] = p.Error()
+		}
+		return fmt.Errorf("invalid config: %s", strings.Join(msgs, "; "))
+	}
+
+	if cfg.Endpoint == config.EndpointEmbeddings {
+		for _, ex := range cfg.Exclude {
+			if strings.Contains(strings.ToLower(ex), "embed") {
+				output.Logger.Warn("Exclude filter will skip embedding models", "filter", ex, "endpoint", cfg.Endpoint)
+			}
+		}
+	}
 	return nil
 }
 
 // runForURL handles the full benchmark cycle for a single backend URL.
-func runForURL(e *Engine, cfg *config.Config, url string, csvWriter *output.CSVWriter, jsonWriter *output.JSONWriter) {
-	// 1. Discovery Phase
-	var models []string
-	var err error
+func runForURL(ctx context.Context, e *Engine, cfg *config.Config, url string, filters *modelFilters, prompts []namedPrompt, done resumeSet, writers []output.ResultWriter, summaryWriter *output.SummaryWriter) {
+	repeat := cfg.Repeat
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	// 1-2. Discovery and Filtering
+	selected, err := selectModels(ctx, e, cfg, url, filters)
+	if err != nil {
+		output.Logger.Error("Failed to discover models", "url", url, "error", err)
+		return
+	}
+
+	// Auto-pull: download explicitly requested models the host doesn't have yet
+	if cfg.AutoPull && len(cfg.Models) > 0 && cfg.ProtocolFor(url) == config.ProtocolOllama {
+		selected = pullMissing(ctx, e, url, selected, writers)
+	}
 
+	// Deterministic order keeps result rows stable between runs
+	selected = orderModels(ctx, e, cfg, url, selected)
+	if cfg.Shuffle {
+		shuffleFor(cfg, selected, url)
+		output.Logger.Info("Shuffled model order", "url", url, "models", selected)
+	}
+	if capped := capModels(cfg, selected); len(capped) < len(selected) {
+		output.Logger.Info("Testing a subset of models (max_models)", "url", url, "tested", len(capped), "of", len(selected), "models", capped)
+		selected = capped
+	}
+
+	// 3. Execution Phase
+	// Models run sequentially unless model_concurrency (or this URL's
+	// url_model_concurrency cap) is > 1 (multi-GPU hosts only).
+	workers := cfg.ModelConcurrencyFor(url)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(selected) {
+		workers = len(selected)
+	}
+
+	modelChan := make(chan string, len(selected))
+	for _, modelName := range selected {
+		modelChan <- modelName
+	}
+	close(modelChan)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pace := &pacer{delay: cfg.InterRunDelay}
+			for modelName := range modelChan {
+				if ctx.Err() != nil {
+					return
+				}
+				// Render per model so {{.Model}} resolves to the model under test
+				modelPrompts, err := renderPrompts(prompts, modelName, url, cfg.Vars)
+				if err != nil {
+					output.Logger.Error("Failed to render prompt", "model", modelName, "url", url, "error", err)
+					continue
+				}
+				runModel(ctx, e, cfg, url, modelName, modelPrompts, repeat, pace, done, writers, summaryWriter)
+				if cfg.UnloadAfter && ctx.Err() == nil && cfg.ProtocolFor(url) == config.ProtocolOllama {
+					unloadModel(ctx, e, url, modelName)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// selectModels returns the models to benchmark on url before auto-pull:
+// cfg.Models if set, otherwise everything the host reports, minus filtered ones.
+func selectModels(ctx context.Context, e *Engine, cfg *config.Config, url string, filters *modelFilters) ([]string, error) {
+	var models []string
 	if len(cfg.Models) > 0 {
 		output.Logger.Info("Using explicit model list", "url", url, "count", len(cfg.Models))
 		models = cfg.Models
 	} else {
 		output.Logger.Info("Discovering models...", "url", url)
-		models, err = e.GetModels(url)
+		var err error
+		models, err = e.GetModels(ctx, url)
 		if err != nil {
-			output.Logger.Error("Failed to discover models", "url", url, "error", err)
-			return
+			return nil, err
 		}
 		output.Logger.Info("Found models", "url", url, "count", len(models))
 	}
 
-	// 2. Execution Phase
+	var selected []string
 	for _, modelName := range models {
-		// Check Exclusions
-		shouldSkip := false
-		for _, ex := range cfg.Exclude {
-			if strings.Contains(strings.ToLower(modelName), strings.ToLower(ex)) {
-				output.Logger.Info("Skipping model (excluded)", "model", modelName, "url", url, "filter", ex)
-				shouldSkip = true
+		// Filters apply to explicit model lists too
+		if keep, reason := filters.keep(modelName); !keep {
+			output.Logger.Info("Skipping model", "model", modelName, "url", url, "reason", reason)
+			continue
+		}
+		selected = append(selected, modelName)
+	}
+	return selected, nil
+}
+
+// ListModels returns the models installed on baseURL that pass the config's
+// include/exclude filters, and the names the filters drop. cfg.Models is ignored:
+// this reports what the host has, not what a run was told to test.
+func (e *Engine) ListModels(ctx context.Context, baseURL string) (kept []model.ModelInfo, skipped []string, err error) {
+	filters, err := newModelFilters(e.Config)
+	if err != nil {
+		return nil, nil, err
+	}
+	models, err := e.GetModelsDetailed(ctx, baseURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, m := range models {
+		if keep, _ := filters.keep(m.Name); keep {
+			kept = append(kept, m)
+		} else {
+			skipped = append(skipped, m.Name)
+		}
+	}
+	return kept, skipped, nil
+}
+
+// orderModels sorts the selected models according to cfg.ModelOrder.
+// Size ordering needs /api/tags; if sizes are unavailable it falls back to name.
+func orderModels(ctx context.Context, e *Engine, cfg *config.Config, url string, models []string) []string {
+	ordered := append([]string(nil), models...)
+	switch cfg.ModelOrder {
+	case config.ModelOrderDiscovery:
+		return ordered
+	case config.ModelOrderSize:
+		if cfg.ProtocolFor(url) == config.ProtocolOllama {
+			sizes, err := e.GetModelSizes(ctx, url)
+			if err == nil {
+				// Smallest first; models without a known size go last, by name
+				sort.SliceStable(ordered, func(i, j int) bool {
+					si, iok := sizes[ordered[i]]
+					sj, jok := sizes[ordered[j]]
+					if iok != jok {
+						return iok
+					}
+					if si != sj {
+						return si < sj
+					}
+					return ordered[i] < ordered[j]
+				})
+				return ordered
+			}
+			output.Logger.Warn("Could not read model sizes, ordering by name", "url", url, "error", err)
+		} else {
+			output.Logger.Warn("Model sizes are unavailable for this protocol, ordering by name", "url", url)
+		}
+	}
+	sort.Strings(ordered)
+	return ordered
+}
+
+// capModels keeps the first cfg.MaxModels models. Models later skipped by
+// --resume still count toward the cap.
+func capModels(cfg *config.Config, models []string) []string {
+	if cfg.MaxModels <= 0 || len(models) <= cfg.MaxModels {
+		return models
+	}
+	return models[:cfg.MaxModels]
+}
+
+// shuffleFor randomizes s in place. The order depends only on cfg.Seed and
+// keys, so it is reproducible no matter how URL and model workers interleave.
+func shuffleFor[T any](cfg *config.Config, s []T, keys ...string) {
+	h := fnv.New64a()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+	}
+	rng := rand.New(rand.NewPCG(uint64(cfg.Seed), h.Sum64()))
+	rng.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// modelFilters holds the include/exclude rules for a run, with regexes compiled once.
+type modelFilters struct {
+	include      []string
+	exclude      []string
+	includeRegex []*regexp.Regexp
+	excludeRegex []*regexp.Regexp
+}
+
+// newModelFilters compiles the configured regex filters, failing on the first invalid pattern.
+func newModelFilters(cfg *config.Config) (*modelFilters, error) {
+	f := &modelFilters{include: cfg.Include, exclude: cfg.Exclude}
+	for _, p := range cfg.IncludeRegex {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include_regex %q: %w", p, err)
+		}
+		f.includeRegex = append(f.includeRegex, re)
+	}
+	for _, p := range cfg.ExcludeRegex {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_regex %q: %w", p, err)
+		}
+		f.excludeRegex = append(f.excludeRegex, re)
+	}
+	return f, nil
+}
+
+// keep decides whether a model is tested. Substrings match case-insensitively;
+// regexes match as written (use (?i) for case-insensitive). If any include rule
+// is set, the model must match at least one of them (OR); exclude rules are then
+// applied as a subtractive filter. When the model is skipped, reason names the rule.
+func (f *modelFilters) keep(modelName string) (keep bool, reason string) {
+	name := strings.ToLower(modelName)
+
+	if len(f.include) > 0 || len(f.includeRegex) > 0 {
+		matched := false
+		for _, in := range f.include {
+			if strings.Contains(name, strings.ToLower(in)) {
+				matched = true
+				break
+			}
+		}
+		for _, re := range f.includeRegex {
+			if matched {
 				break
 			}
+			matched = re.MatchString(modelName)
+		}
+		if !matched {
+			return false, "not included"
+		}
+	}
+
+	for _, ex := range f.exclude {
+		if strings.Contains(name, strings.ToLower(ex)) {
+			return false, "excluded by " + strconv.Quote(ex)
 		}
-		if shouldSkip {
+	}
+	for _, re := range f.excludeRegex {
+		if re.MatchString(modelName) {
+			return false, "excluded by /" + re.String() + "/"
+		}
+	}
+	return true, ""
+}
+
+// pullMissing pulls every model in selected that the host doesn't report and
+// returns the models that are available afterwards. A failed pull is recorded
+// as an error result for that model; the rest of the run continues.
+func pullMissing(ctx context.Context, e *Engine, url string, selected []string, writers []output.ResultWriter) []string {
+	installed, err := e.GetModels(ctx, url)
+	if err != nil {
+		output.Logger.Error("Failed to list installed models; skipping auto-pull", "url", url, "error", err)
+		return selected
+	}
+
+	var ready []string
+	for _, modelName := range selected {
+		if hasModel(installed, modelName) {
+			ready = append(ready, modelName)
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		output.Logger.Info("Model not present on host, pulling", "model", modelName, "url", url)
+		if err := e.PullModel(ctx, url, modelName); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			output.Logger.Error("Host could not pull model. Skipping it.", "model", modelName, "url", url, "error", err)
+			kind, status := errorDetails(err)
+			writeResult(e.Config, model.Result{
+				Model:      modelName,
+				URL:        url,
+				Timestamp:  time.Now(),
+				Error:      fmt.Sprintf("pull failed: %v", err),
+				ErrorKind:  kind,
+				StatusCode: status,
+			}, writers)
 			continue
 		}
+		output.Logger.Info("Pull complete", "model", modelName, "url", url)
+		ready = append(ready, modelName)
+	}
+	return ready
+}
 
-		output.Logger.Info("Testing Model", "model", modelName, "url", url)
+// unloadModel evicts a model after its configs complete so the next model
+// starts cold, then polls /api/ps to confirm it actually left memory.
+func unloadModel(ctx context.Context, e *Engine, url, modelName string) {
+	if err := e.UnloadModel(ctx, url, modelName); err != nil {
+		output.Logger.Warn("Failed to unload model", "model", modelName, "url", url, "error", err)
+		return
+	}
 
-		// A. Stream Test (Health Check)
-		err := e.StreamInference(url, modelName, cfg.Prompt)
-		if err != nil {
-			output.Logger.Error("Stream Inference Failed", "model", modelName, "url", url, "error", err)
-		} else {
-			output.Logger.Info("Stream Inference Success", "model", modelName, "url", url)
+	// Eviction is asynchronous on the server; give it a few seconds
+	for i := 0; i < 10; i++ {
+		size, _, err := e.GetRunningModelInfo(ctx, url, modelName)
+		if errors.Is(err, errNoPS) {
+			output.Logger.Info("Model unload requested (not verifiable without /api/ps)", "model", modelName, "url", url)
+			return
+		}
+		if err == nil && size == 0 {
+			output.Logger.Info("Model unloaded", "model", modelName, "url", url)
+			return
+		}
+		if sleepCtx(ctx, 500*time.Millisecond) != nil {
+			return
 		}
+	}
+	output.Logger.Warn("Model still resident after unload request", "model", modelName, "url", url)
+}
 
-		// B. Metric Tests (Configs)
+// pacer spaces out one worker's runs by cfg.InterRunDelay. The first wait
+// returns immediately, so nothing sleeps before the first run or after the last.
+type pacer struct {
+	delay  time.Duration
+	primed bool
+}
+
+// wait sleeps for the delay unless this is the worker's first wait, or ctx is cancelled.
+func (p *pacer) wait(ctx context.Context) {
+	if p.primed && p.delay > 0 {
+		sleepCtx(ctx, p.delay)
+	}
+	p.primed = true
+}
+
+// pendingRun is one (prompt, config) pair still to be benchmarked for a model.
+type pendingRun struct {
+	prompt   namedPrompt
+	inferCfg map[string]interface{}
+}
+
+// pendingRuns lists the (prompt, config) pairs to benchmark for a model, in run order.
+// Resume: only pairs that haven't succeeded previously are pending.
+func pendingRuns(cfg *config.Config, url, modelName string, prompts []namedPrompt, done resumeSet) []pendingRun {
+	var pending []pendingRun
+	for _, p := range prompts {
+		if cfg.Endpoint == config.EndpointEmbeddings {
+			if !done.has(url, modelName, p.Name, nil) {
+				pending = append(pending, pendingRun{prompt: p})
+			}
+			continue
+		}
 		for _, inferCfg := range cfg.InferConfigs {
-			output.Logger.Info("Running Inference Config", "model", modelName, "url", url, "config", inferCfg)
+			if done.has(url, modelName, p.Name, inferCfg) {
+				continue
+			}
+			run := pendingRun{prompt: p, inferCfg: inferCfg}
+			if len(cfg.CtxSweep) > 0 {
+				run.prompt.Text = padPrompt(p.Text, inferCfg)
+			}
+			pending = append(pending, run)
+		}
+	}
+	if cfg.Shuffle {
+		shuffleFor(cfg, pending, url, modelName)
+	}
+	return pending
+}
 
-			res, err := e.Inference(url, modelName, cfg.Prompt, inferCfg)
-			if err != nil {
-				output.Logger.Error("Inference Benchmark Failed. Skipping remaining configs for this model.", "model", modelName, "url", url, "config", inferCfg, "error", err)
-				res.Error = err.Error()
-
-				// Attempt to capture VRAM Stats even on error (robustness)
-				size, vram, vramErr := e.GetRunningModelInfo(url, modelName)
-				if vramErr == nil && size > 0 {
-					res.MemoryUsage = size
-					res.VRAMUsage = vram
-					res.VRAMPercentage = float64(vram) / float64(size) * 100.0
-				}
+// runModel runs the stream test and all metric configs for a single model,
+// once per prompt.
+func runModel(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, prompts []namedPrompt, repeat int, pace *pacer, done resumeSet, writers []output.ResultWriter, summaryWriter *output.SummaryWriter) {
+	pending := pendingRuns(cfg, url, modelName, prompts, done)
+	if len(pending) == 0 {
+		output.Logger.Info("Skipping model (already benchmarked)", "model", modelName, "url", url)
+		return
+	}
 
-				// Write partial result
-				if err := csvWriter.Write(res); err != nil {
-					output.Logger.Error("Failed to write partial result to CSV", "error", err)
-				}
-				if err := jsonWriter.Write(res); err != nil {
-					output.Logger.Error("Failed to write partial result to JSON", "error", err)
-				}
-				break // Cruiser Protocol: Don't keep testing if the tree is rotting
+	// Between models: the previous model's runs finished on this worker
+	pace.wait(ctx)
+	output.Logger.Info("Testing Model", "model", modelName, "url", url)
+
+	// Pre-load VRAM headroom check (max_vram_bytes): skip models that can't fit
+	if err := checkHeadroom(ctx, e, cfg, url, modelName); err != nil {
+		output.Logger.Error("Insufficient VRAM headroom. Skipping model.", "model", modelName, "url", url, "error", err)
+		kind, status := errorDetails(err)
+		writeResult(cfg, model.Result{
+			Model:      modelName,
+			URL:        url,
+			Timestamp:  time.Now(),
+			Error:      err.Error(),
+			ErrorKind:  kind,
+			StatusCode: status,
+		}, writers)
+		return
+	}
+
+	// Image runs need a vision model; /api/show says which ones are
+	if len(cfg.Images) > 0 && cfg.ProtocolFor(url) == config.ProtocolOllama && cfg.Endpoint == config.EndpointGenerate {
+		ok, err := e.IsMultimodal(ctx, url, modelName)
+		if err != nil {
+			output.Logger.Warn("Could not check for vision support; benchmarking anyway", "model", modelName, "url", url, "error", err)
+		} else if !ok {
+			output.Logger.Info("Skipping model: not multimodal per /api/show (images configured)", "model", modelName, "url", url)
+			return
+		}
+	}
+
+	// Embedding models cannot generate, so they skip the stream test and configs.
+	if cfg.Endpoint == config.EndpointEmbeddings {
+		for i, run := range pending {
+			if i > 0 {
+				pace.wait(ctx)
+			}
+			if ctx.Err() != nil {
+				return
 			}
+			runEmbedding(ctx, e, cfg, url, modelName, run.prompt, writers)
+		}
+		return
+	}
 
-			// Capture VRAM Stats (Model is likely still loaded)
-			size, vram, err := e.GetRunningModelInfo(url, modelName)
-			if err == nil && size > 0 {
-				res.MemoryUsage = size
-				res.VRAMUsage = vram
-				res.VRAMPercentage = float64(vram) / float64(size) * 100.0
+	// A. Stream Test (Health Check)
+	// TTFT from the stream is attached to every metric row for this model.
+	// With stream_benchmark the stream runs the first config and stands in
+	// for its first measured run. skip_health_check leaves it out;
+	// health_check_only records it as the model's only result.
+	var ttft time.Duration
+	var streamRun *model.Result
+	if cfg.HealthCheckOnly && cfg.ProtocolFor(url) != config.ProtocolOllama {
+		output.Logger.Warn("No stream test on OpenAI-compatible backends. Skipping model (health_check_only).", "model", modelName, "url", url)
+		return
+	}
+	if cfg.ProtocolFor(url) == config.ProtocolOllama && !cfg.SkipHealthCheck {
+		var stream model.Result
+		var options map[string]interface{}
+		benchmark := cfg.StreamBenchmark && cfg.Endpoint == config.EndpointGenerate
+		if benchmark {
+			options = pending[0].inferCfg
+		}
+		warm := checkWarmStart(ctx, e, cfg, url, modelName)
+		var err error
+		ttft, err = e.StreamInference(ctx, url, modelName, pending[0].prompt.Text, pending[0].prompt.Images, options, &stream)
+		if err != nil {
+			output.Logger.Error("Stream Inference Failed", "model", modelName, "url", url, "error", err)
+			skipModel := cfg.FailFast == config.FailFastModel && !cfg.HealthCheckOnly
+			if (cfg.HealthCheckOnly || skipModel) && ctx.Err() == nil {
+				kind, status := errorDetails(err)
+				writeResult(cfg, model.Result{
+					Model:      modelName,
+					URL:        url,
+					Config:     options,
+					PromptName: pending[0].prompt.Name,
+					Timestamp:  time.Now(),
+					Iteration:  1,
+					Error:      err.Error(),
+					ErrorKind:  kind,
+					StatusCode: status,
+				}, writers)
+			}
+			if skipModel {
+				output.Logger.Error("Health check failed. Skipping model (fail_fast=model).", "model", modelName, "url", url)
+				return
 			}
+		} else {
+			output.Logger.Info("Stream Inference Success", "model", modelName, "url", url, "ttft", ttft,
+				"tokens_gen", stream.TokensGenerated,
+				"tokens_per_sec", fmt.Sprintf("%.1f", stream.TokensPerSecond),
+			)
+			if benchmark || cfg.HealthCheckOnly {
+				stream.PromptName = pending[0].prompt.Name
+				stream.ImageCount = len(pending[0].prompt.Images)
+				stream.ImageBytes = pending[0].prompt.imageBytes
+				stream.WarmStart = warm
+				checkJSON(cfg, url, &stream)
+				stream.Iteration = 1
+				captureVRAM(ctx, e, cfg, url, modelName, &stream)
+				writeResult(cfg, stream, writers)
+				streamRun = &stream
+			}
+		}
+	}
+	if cfg.HealthCheckOnly {
+		return
+	}
 
-			if res.TokensGenerated == 0 {
-				output.Logger.Warn("Model returned success but generated 0 tokens. Context limit exceeded?", "model", modelName)
+	// Warmup: load the model so measured runs report steady-state numbers
+	if cfg.Warmup && cfg.KeepAlive != "0" && cfg.ProtocolFor(url) == config.ProtocolOllama {
+		output.Logger.Info("Warming up model", "model", modelName, "url", url)
+		if err := e.Warmup(ctx, url, modelName, pending[0].inferCfg); err != nil {
+			output.Logger.Warn("Warmup failed", "model", modelName, "url", url, "error", err)
+		}
+	}
+
+	// B. Metric Tests (Prompts x Configs)
+	for i, run := range pending {
+		var runs []model.Result
+		first := 1
+		if i == 0 && streamRun != nil {
+			runs = append(runs, *streamRun)
+			first = 2
+		}
+		failed := false
+		for iter := first; iter <= repeat && ctx.Err() == nil; iter++ {
+			if i > 0 || iter > 1 {
+				pace.wait(ctx)
+			}
+			res, err := runConfig(ctx, e, cfg, url, modelName, run.prompt, run.inferCfg, ttft, iter, writers)
+			if err != nil {
+				// fail_fast=none: record every error and keep going
+				if cfg.FailFast == config.FailFastNone && ctx.Err() == nil {
+					continue
+				}
+				// Only this config is too small for the prompt; the others may fit
+				if kind, _ := errorDetails(err); kind != ErrorKindContext {
+					failed = true
+				}
+				break
 			}
+			runs = append(runs, res)
+		}
 
-			output.Logger.Info("Inference Success",
+		// Summarize repeats, discarding the first (warmup) run
+		if summaryWriter != nil && len(runs) > 1 {
+			agg := model.NewAggregate(runs[1:], 1)
+			output.Logger.Info("Repeat Summary",
 				"model", modelName,
 				"url", url,
-				"duration", res.Duration,
-				"tokens_gen", res.TokensGenerated,
-				"vram_pct", fmt.Sprintf("%.1f%%", res.VRAMPercentage),
+				"prompt", run.prompt.Name,
+				"config", run.inferCfg,
+				"runs", agg.Runs,
+				"tokens_per_sec_mean", fmt.Sprintf("%.1f", agg.TokensPerSecond.Mean),
+				"tokens_per_sec_p95", fmt.Sprintf("%.1f", agg.TokensPerSecond.P95),
+				"latency_p99_s", fmt.Sprintf("%.3f", agg.Latency.P99),
 			)
-
-			// Write Result
-			if err := csvWriter.Write(res); err != nil {
-				output.Logger.Error("Failed to write result to CSV", "error", err)
+			if err := summaryWriter.Write(agg); err != nil {
+				output.Logger.Error("Failed to write summary", "error", err)
 			}
-			if err := jsonWriter.Write(res); err != nil {
-				output.Logger.Error("Failed to write result to JSON", "error", err)
-			}
-			// Optional: Sleep between runs?
-			time.Sleep(1 * time.Second)
 		}
+
+		if failed || ctx.Err() != nil {
+			break // Cruiser Protocol: Don't keep testing if the tree is rotting (fail_fast=config/model)
+		}
+	}
+}
+
+// failFastWriter cancels the run at the first failed result (fail_fast_run).
+type failFastWriter struct {
+	cancel context.CancelCauseFunc
+	once   sync.Once
+}
+
+func (f *failFastWriter) Write(r model.Result) error {
+	if r.Error != "" {
+		f.once.Do(func() {
+			output.Logger.Error("Aborting run at the first failure (fail_fast_run)", "model", r.Model, "url", r.URL, "error", r.Error)
+			f.cancel(ErrFailFast)
+		})
+	}
+	return nil
+}
+
+func (f *failFastWriter) Close() error { return nil }
+
+// runConfig executes one measured inference for a config and writes the result.
+// The returned error signals that the remaining configs for the model should be
+// skipped, except context_exceeded, which only ends this config's repeats
+// (fail_fast=none skips nothing).
+func runConfig(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, prompt namedPrompt, inferCfg map[string]interface{}, ttft time.Duration, iteration int, writers []output.ResultWriter) (model.Result, error) {
+	output.Logger.Info("Running Inference Config", "model", modelName, "url", url, "prompt", prompt.Name, "config", inferCfg, "iteration", iteration)
+
+	warm := checkWarmStart(ctx, e, cfg, url, modelName)
+	res, err := runInference(ctx, e, cfg, url, modelName, prompt, inferCfg)
+	res.PromptName = prompt.Name
+	res.TimeToFirstToken = ttft
+	res.Iteration = iteration
+	res.WarmStart = warm
+	if ctx.Err() != nil {
+		// Interrupted mid-request: the measurement is meaningless, don't record it
+		output.Logger.Warn("Inference interrupted", "model", modelName, "url", url, "config", inferCfg)
+		return res, ctx.Err()
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.ErrorKind, res.StatusCode = errorDetails(err)
+		if cfg.FailFast == config.FailFastNone {
+			output.Logger.Error("Inference Benchmark Failed", "model", modelName, "url", url, "config", inferCfg, "error", err)
+		} else if res.ErrorKind == ErrorKindContext {
+			output.Logger.Error("Prompt exceeds the context window. Skipping this config.", "model", modelName, "url", url, "config", inferCfg, "error", err)
+		} else {
+			output.Logger.Error("Inference Benchmark Failed. Skipping remaining configs for this model.", "model", modelName, "url", url, "config", inferCfg, "error", err)
+		}
+
+		// Attempt to capture VRAM Stats even on error (robustness)
+		captureVRAM(ctx, e, cfg, url, modelName, &res)
+
+		// Write partial result
+		writeResult(cfg, res, writers)
+		return res, err
+	}
+
+	// Capture VRAM Stats (Model is likely still loaded)
+	captureVRAM(ctx, e, cfg, url, modelName, &res)
+
+	// A truncated prompt measured a different workload; record it as failed
+	if err := contextExceeded(res, inferCfg); err != nil {
+		msg := "Prompt exceeds the context window. Skipping this config."
+		if cfg.FailFast == config.FailFastNone {
+			msg = "Prompt exceeds the context window"
+		}
+		output.Logger.Error(msg, "model", modelName, "url", url, "config", inferCfg, "error", err)
+		res.Error = err.Error()
+		res.ErrorKind = ErrorKindContext
+		writeResult(cfg, res, writers)
+		return res, err
+	}
+
+	checkJSON(cfg, url, &res)
+	if cfg.VerifyDeterminism {
+		verifyDeterminism(ctx, e, cfg, url, modelName, prompt, inferCfg, &res)
+	}
+
+	if res.TokensGenerated == 0 {
+		output.Logger.Warn("Model returned success but generated 0 tokens", "model", modelName, "prompt_tokens", res.PromptEvalCount)
+	}
+
+	output.Logger.Info("Inference Success",
+		"model", modelName,
+		"url", url,
+		"duration", res.Duration,
+		"tokens_gen", res.TokensGenerated,
+		"prompt_tokens", res.PromptEvalCount,
+		"done_reason", res.DoneReason,
+		"vram_pct", fmt.Sprintf("%.1f%%", res.VRAMPercentage),
+	)
+	if cfg.MaxTokens > 0 {
+		if res.DoneReason == "length" {
+			output.Logger.Info("Generation hit the max_tokens cap", "model", modelName, "tokens_gen", res.TokensGenerated, "cap", inferCfg["num_predict"])
+		} else {
+			output.Logger.Debug("Generation stopped before the max_tokens cap", "model", modelName, "tokens_gen", res.TokensGenerated, "done_reason", res.DoneReason)
+		}
+	}
+
+	// Write Result
+	writeResult(cfg, res, writers)
+	return res, nil
+}
+
+// runInference dispatches a metric run to the protocol and endpoint selected in config.
+// In chat mode (and always for OpenAI backends) the configured messages are sent
+// as context before the prompt. Images are only sent on the generate endpoint.
+func runInference(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, prompt namedPrompt, inferCfg map[string]interface{}) (model.Result, error) {
+	if cfg.ProtocolFor(url) == config.ProtocolOpenAI {
+		return e.OpenAIInference(ctx, url, modelName, chatMessages(cfg, prompt.Text), inferCfg)
+	}
+	if cfg.Endpoint == config.EndpointChat {
+		return e.ChatInference(ctx, url, modelName, chatMessages(cfg, prompt.Text), inferCfg)
+	}
+	res, err := e.Inference(ctx, url, modelName, prompt.Text, prompt.Images, inferCfg)
+	res.ImageCount = len(prompt.Images)
+	res.ImageBytes = prompt.imageBytes
+	return res, err
+}
+
+// verifyDeterminism repeats a successful request and records in res whether
+// the second response is identical. A failed repeat leaves it unchecked.
+func verifyDeterminism(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, prompt namedPrompt, inferCfg map[string]interface{}, res *model.Result) {
+	again, err := runInference(ctx, e, cfg, url, modelName, prompt, inferCfg)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		output.Logger.Warn("Determinism check failed", "model", modelName, "url", url, "config", inferCfg, "error", err)
+		return
+	}
+
+	// Hashes keep the comparison cheap however long the responses are
+	match := sha256.Sum256([]byte(res.Response)) == sha256.Sum256([]byte(again.Response))
+	res.Deterministic = &match
+	if !match {
+		output.Logger.Warn("Nondeterministic response: a repeat of the same request differed",
+			"model", modelName, "url", url, "config", inferCfg)
+	}
+}
+
+// chatMessages builds the conversation for chat-style requests.
+func chatMessages(cfg *config.Config, prompt string) []model.Message {
+	messages := make([]model.Message, 0, len(cfg.Messages)+1)
+	messages = append(messages, cfg.Messages...)
+	return append(messages, model.Message{Role: "user", Content: prompt})
+}
+
+// captureVRAM records memory placement for a model from /api/ps.
+// OpenAI-compatible backends have no /api/ps, so they are skipped.
+func captureVRAM(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, res *model.Result) {
+	if cfg.ProtocolFor(url) != config.ProtocolOllama {
+		return
+	}
+	size, vram, err := e.GetRunningModelInfo(ctx, url, modelName)
+	if err == nil && size > 0 {
+		res.MemoryUsage = size
+		res.VRAMUsage = vram
+		res.VRAMPercentage = float64(vram) / float64(size) * 100.0
+	}
+}
+
+// checkWarmStart reports whether modelName is already resident on url before a
+// request, from /api/ps. nil means unknown: OpenAI-compatible backends, hosts
+// without /api/ps, or a failed lookup. Only an exact name match counts (as in
+// checkHeadroom): a resident llama3.1:8b does not make llama3 warm.
+func checkWarmStart(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string) *bool {
+	if cfg.ProtocolFor(url) != config.ProtocolOllama {
+		return nil
+	}
+	running, err := e.runningModels(ctx, url)
+	if err != nil {
+		if !errors.Is(err, errNoPS) {
+			output.Logger.Debug("Could not check whether the model is loaded", "model", modelName, "url", url, "error", err)
+		}
+		return nil
+	}
+	warm := false
+	for _, m := range running {
+		if withTag(m.Name) == withTag(modelName) {
+			warm = true
+			break
+		}
+	}
+	return &warm
+}
+
+// runEmbedding benchmarks a single embedding request for a model and writes the result.
+func runEmbedding(ctx context.Context, e *Engine, cfg *config.Config, url, modelName string, prompt namedPrompt, writers []output.ResultWriter) {
+	warm := checkWarmStart(ctx, e, cfg, url, modelName)
+	res, err := e.EmbedInference(ctx, url, modelName, prompt.Text)
+	res.PromptName = prompt.Name
+	res.Iteration = 1
+	res.WarmStart = warm
+	if ctx.Err() != nil {
+		output.Logger.Warn("Embedding interrupted", "model", modelName, "url", url)
+		return
+	}
+	if err != nil {
+		output.Logger.Error("Embedding Benchmark Failed", "model", modelName, "url", url, "error", err)
+		res.Error = err.Error()
+		res.ErrorKind, res.StatusCode = errorDetails(err)
+	}
+
+	// Capture VRAM Stats (Model is likely still loaded)
+	captureVRAM(ctx, e, cfg, url, modelName, &res)
+
+	if err == nil {
+		output.Logger.Info("Embedding Success",
+			"model", modelName,
+			"url", url,
+			"duration", res.Duration,
+			"vector_dim", res.VectorDim,
+			"vram_pct", fmt.Sprintf("%.1f%%", res.VRAMPercentage),
+		)
+	}
+
+	writeResult(cfg, res, writers)
+}
+
+// writeResult records a result in every enabled output.
+// The response text is dropped or shortened first according to the config.
+func writeResult(cfg *config.Config, res model.Result, writers []output.ResultWriter) {
+	res.Response = trimResponse(res.Response, cfg.IncludeResponse, cfg.ResponseMaxChars)
+	res.RunID, res.Labels = cfg.RunID, cfg.Labels
+	res.EstCostPer1MTokens = cfg.CostPer1MTokens(res.TokensPerSecond)
+	for _, w := range writers {
+		if err := w.Write(res); err != nil {
+			output.Logger.Error("Failed to write result", "writer", fmt.Sprintf("%T", w), "error", err)
+		}
+	}
+}
+
+// trimResponse blanks the response when it is excluded, or cuts it to maxChars
+// characters (runes) with a trailing ellipsis. maxChars <= 0 means no limit.
+func trimResponse(response string, include bool, maxChars int) string {
+	if !include {
+		return ""
+	}
+	if maxChars <= 0 || utf8.RuneCountInString(response) <= maxChars {
+		return response
 	}
+	runes := []rune(response)
+	return string(runes[:maxChars]) + "…"
 }
@@ -0,0 +1,162 @@
+package engine
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/config"
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+func TestCheckWarmStart(t *testing.T) {
+	srv := newFakeOllama(t)
+	srv.running = []runningModel{{Name: "llama3.1:8b", Size: 9 << 30, SizeVRAM: 9 << 30}, {Name: "mistral:latest", Size: 4 << 30}}
+	cfg := testConfig(t, srv.URL)
+	e := New(cfg)
+
+	tests := []struct {
+		model string
+		warm  bool
+	}{
+		{model: "llama3.1:8b", warm: true},
+		{model: "mistral", warm: true}, // Implicit :latest
+		{model: "llama3", warm: false}, // Only a prefix of a resident model
+		{model: "llama3.1", warm: false},
+	}
+	for _, tt := range tests {
+		got := checkWarmStart(context.Background(), e, cfg, srv.URL, tt.model)
+		if got == nil || *got != tt.warm {
+			t.Errorf("checkWarmStart(%q) = %v, want %v", tt.model, got, tt.warm)
+		}
+	}
+
+	srv.noPS = true
+	noPS := New(cfg)
+	if got := checkWarmStart(context.Background(), noPS, cfg, srv.URL, "llama3.1:8b"); got != nil {
+		t.Errorf("checkWarmStart without /api/ps = %v, want nil (unknown)", *got)
+	}
+}
+
+func TestCheckConfigRejectsModes(t *testing.T) {
+	tests := []struct {
+		field string
+		set   func(*config.Config)
+	}{
+		{field: "endpoint", set: func(c *config.Config) { c.Endpoint = "completions" }},
+		{field: "fail_fast", set: func(c *config.Config) { c.FailFast = "sometimes" }},
+		{field: "model_order", set: func(c *config.Config) { c.ModelOrder = "random" }},
+		{field: "outputs[1]", set: func(c *config.Config) { c.Outputs = []string{config.OutputJSON, "xml"} }},
+		{field: "format", set: func(c *config.Config) { c.Format = "yaml" }},
+		{field: "health_check_only", set: func(c *config.Config) { c.SkipHealthCheck, c.HealthCheckOnly = true, true }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			cfg := testConfig(t, "http://127.0.0.1:1", "llama3:8b")
+			if err := checkConfig(cfg); err != nil {
+				t.Fatalf("valid config rejected: %v", err)
+			}
+			tt.set(cfg)
+			err := checkConfig(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.field+":") {
+				t.Fatalf("checkConfig = %v, want the %s problem", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestCheckConfigLeavesWarningsToRun(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(*config.Config)
+	}{
+		// selectModels drops it with a warning ("Exclude filters still apply to this list")
+		{name: "explicit model excluded", set: func(c *config.Config) { c.Models, c.Exclude = []string{"nomic-embed-text"}, []string{"embed"} }},
+		// Run clamps to 1 with a warning
+		{name: "concurrency below 1", set: func(c *config.Config) { c.Concurrency = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t, "http://127.0.0.1:1", "llama3:8b")
+			tt.set(cfg)
+			if err := checkConfig(cfg); err != nil {
+				t.Fatalf("checkConfig = %v, want the run to go ahead", err)
+			}
+		})
+	}
+}
+
+func TestMetricsPortInUseLeavesNoFiles(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b")
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	cfg := testConfig(t, srv.URL, "llama3:8b")
+	cfg.Outputs = []string{config.OutputCSV, config.OutputJSON}
+	cfg.MetricsAddr = busy.Addr().String()
+	if err := Run(context.Background(), cfg); err == nil || !strings.Contains(err.Error(), "metrics") {
+		t.Fatalf("Run = %v, want a metrics server error", err)
+	}
+
+	entries, err := os.ReadDir(cfg.OutputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		t.Errorf("left %s in the output directory", e.Name())
+	}
+	if srv.hitCount("/api/generate") != 0 {
+		t.Error("inference ran despite the metrics error")
+	}
+}
+
+// resultRecorder is a ResultWriter that keeps every row written.
+type resultRecorder struct{ rows []model.Result }
+
+func (r *resultRecorder) Write(res model.Result) error { r.rows = append(r.rows, res); return nil }
+func (r *resultRecorder) Close() error                 { return nil }
+
+func TestPlacementAbortRowKeepsIdentity(t *testing.T) {
+	srv := newFakeOllama(t, "llama3:8b", "nomic-embed-text")
+	// Half on CPU while the request is still "loading"
+	srv.running = []runningModel{
+		{Name: "llama3:8b", Size: 8 << 30, SizeVRAM: 4 << 30},
+		{Name: "nomic-embed-text:latest", Size: 1 << 30, SizeVRAM: 1 << 29},
+	}
+	srv.delay = 5 * time.Second
+	cfg := testConfig(t, srv.URL)
+	cfg.GPUOnly = true
+	e := New(cfg)
+	prompt := namedPrompt{Name: "short.txt", Text: "hi"}
+	inferCfg := map[string]interface{}{"num_ctx": 2048}
+
+	rec := &resultRecorder{}
+	writers := []output.ResultWriter{rec}
+	if _, err := runConfig(context.Background(), e, cfg, srv.URL, "llama3:8b", prompt, inferCfg, 0, 1, writers); err == nil {
+		t.Fatal("runConfig succeeded, want a placement abort")
+	}
+	runEmbedding(context.Background(), e, cfg, srv.URL, "nomic-embed-text", prompt, writers)
+
+	if len(rec.rows) != 2 {
+		t.Fatalf("got %d rows, want one inference and one embedding abort", len(rec.rows))
+	}
+	for i, want := range []string{"llama3:8b", "nomic-embed-text"} {
+		row := rec.rows[i]
+		if row.ErrorKind != ErrorKindPlacement {
+			t.Errorf("%s: error_kind = %q, want %q (%s)", want, row.ErrorKind, ErrorKindPlacement, row.Error)
+		}
+		if row.Model != want || row.URL != srv.URL || row.PromptName != "short.txt" || row.Timestamp.IsZero() {
+			t.Errorf("abort row lost its identity: model=%q url=%q prompt=%q timestamp=%v", row.Model, row.URL, row.PromptName, row.Timestamp)
+		}
+	}
+	if cfg := rec.rows[0].Config; cfg["num_ctx"] != 2048 {
+		t.Errorf("inference abort row config = %v, want num_ctx 2048", cfg)
+	}
+}
@@ -0,0 +1,232 @@
+/*
+PURPOSE:
+  Tallies results while a fleet cruise runs and logs an at-a-glance summary
+  at the end, so a quick look tells whether the run went well without opening the CSV.
+
+REQUIREMENTS:
+  User-specified:
+  - Total models tested, successes, failures, fastest/slowest model by
+    tokens/sec, and total wall time, logged after all URLs finish.
+  - Per-URL breakdown when more than one URL was targeted.
+  - Cross-fleet line per model and config run on several URLs: min/mean/max tokens/sec
+    and the fastest URL ("which backend should serve model X?").
+  - Thread-safe: URL and model workers report concurrently.
+
+  Implementation-discovered:
+  - Implemented as an output.ResultWriter (like runMetrics) so every result,
+    including pull failures, is counted through writeResult.
+  - A model counts as failed if any of its runs failed, since the runner stops
+    testing a model after its first failure.
+  - Speed is the mean tokens/sec of a model's successful runs; models without a
+    rate (embeddings) are left out of fastest/slowest.
+  - Models skipped by --resume produce no results and are not counted.
+  - The fleet lines come from model.NewFleetAggregates, the same function the
+    report command uses; only the fields it needs are kept per result.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - None; Write never fails.
+
+IMPLEMENTATION RULES:
+  - Key models by (url, model): the same model on two hosts is two entries.
+
+USAGE:
+  stats := newRunStats(time.Now())
+  writers = append(writers, stats)
+  ...
+  stats.log()
+
+SELF-HEALING INSTRUCTIONS:
+  - Summary shows 0 models after a --resume run: everything was already benchmarked.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/engine/metrics.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+	"github.com/daryltucker/forest-runner/internal/output"
+)
+
+// runStats accumulates per-URL, per-model outcomes for the end-of-run summary.
+type runStats struct {
+	mu     sync.Mutex
+	start  time.Time
+	models map[string]map[string]*modelStats // url -> model -> stats
+	rated  []model.Result                    // Successful runs with a rate, for the fleet view
+}
+
+// modelStats is the outcome of one model on one backend.
+type modelStats struct {
+	failed bool
+	tpsSum float64
+	tpsN   int
+}
+
+// modelSpeed names a model and its mean tokens/sec for fastest/slowest reporting.
+type modelSpeed struct {
+	model string
+	url   string
+	tps   float64
+}
+
+// statsTotals is the summary for the whole run or a single URL.
+type statsTotals struct {
+	models, ok, failed int
+	fastest, slowest   *modelSpeed
+}
+
+// newRunStats starts tallying a run that began at start.
+func newRunStats(start time.Time) *runStats {
+	return &runStats{start: start, models: make(map[string]map[string]*modelStats)}
+}
+
+// Write records a single result.
+func (s *runStats) Write(r model.Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	byModel, ok := s.models[r.URL]
+	if !ok {
+		byModel = make(map[string]*modelStats)
+		s.models[r.URL] = byModel
+	}
+	m, ok := byModel[r.Model]
+	if !ok {
+		m = &modelStats{}
+		byModel[r.Model] = m
+	}
+
+	if r.Error != "" {
+		m.failed = true
+	} else if r.TokensPerSecond > 0 {
+		m.tpsSum += r.TokensPerSecond
+		m.tpsN++
+		s.rated = append(s.rated, model.Result{Model: r.Model, URL: r.URL, TokensPerSecond: r.TokensPerSecond})
+	}
+	return nil
+}
+
+// Close is a no-op; the summary is logged by log.
+func (s *runStats) Close() error {
+	return nil
+}
+
+// log writes the run summary, plus one line per URL and one per model run on
+// several URLs when several were targeted.
+func (s *runStats) log() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	urls := make([]string, 0, len(s.models))
+	for url := range s.models {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	var total statsTotals
+	for _, url := range urls {
+		t := s.totals(url)
+		if len(urls) > 1 {
+			output.Logger.Info("URL Summary", t.attrs("url", url)...)
+		}
+		total.merge(t)
+	}
+
+	attrs := total.attrs("wall_time", time.Since(s.start).Round(time.Millisecond))
+	if len(urls) > 1 {
+		attrs = append(attrs, "urls", len(urls))
+	}
+	output.Logger.Info("Run Summary", attrs...)
+
+	if len(urls) > 1 {
+		for _, f := range model.NewFleetAggregates(s.rated) {
+			if f.URLs < 2 {
+				continue
+			}
+			cfgBytes, _ := json.Marshal(f.Config)
+			attrs := []any{"model", f.Model, "config", string(cfgBytes)}
+			if f.PromptName != "" {
+				attrs = append(attrs, "prompt", f.PromptName)
+			}
+			output.Logger.Info("Fleet Summary", append(attrs, "urls", f.URLs,
+				"min_tokens_per_sec", fmt.Sprintf("%.1f", f.MinTPS),
+				"mean_tokens_per_sec", fmt.Sprintf("%.1f", f.MeanTPS),
+				"max_tokens_per_sec", fmt.Sprintf("%.1f", f.MaxTPS),
+				"fastest_url", f.FastestURL,
+			)...)
+		}
+	}
+}
+
+// totals summarizes the models tested against one URL.
+func (s *runStats) totals(url string) statsTotals {
+	var t statsTotals
+	for name, m := range s.models[url] {
+		t.models++
+		if m.failed {
+			t.failed++
+		} else {
+			t.ok++
+		}
+		if m.tpsN > 0 {
+			t.observe(&modelSpeed{model: name, url: url, tps: m.tpsSum / float64(m.tpsN)})
+		}
+	}
+	return t
+}
+
+// observe updates fastest/slowest with a model's speed. Ties go to the
+// alphabetically first model so the summary is stable between runs.
+func (t *statsTotals) observe(sp *modelSpeed) {
+	if sp == nil {
+		return
+	}
+	if t.fastest == nil || sp.tps > t.fastest.tps || (sp.tps == t.fastest.tps && sp.label() < t.fastest.label()) {
+		t.fastest = sp
+	}
+	if t.slowest == nil || sp.tps < t.slowest.tps || (sp.tps == t.slowest.tps && sp.label() < t.slowest.label()) {
+		t.slowest = sp
+	}
+}
+
+// merge folds another URL's totals into t.
+func (t *statsTotals) merge(o statsTotals) {
+	t.models += o.models
+	t.ok += o.ok
+	t.failed += o.failed
+	t.observe(o.fastest)
+	t.observe(o.slowest)
+}
+
+// attrs renders the totals as logger key/value pairs, after any leading pairs.
+func (t statsTotals) attrs(lead ...any) []any {
+	attrs := append(lead, "models", t.models, "ok", t.ok, "failed", t.failed)
+	if t.fastest != nil {
+		attrs = append(attrs,
+			"fastest", t.fastest.label(), "fastest_tokens_per_sec", fmt.Sprintf("%.1f", t.fastest.tps),
+			"slowest", t.slowest.label(), "slowest_tokens_per_sec", fmt.Sprintf("%.1f", t.slowest.tps),
+		)
+	}
+	return attrs
+}
+
+// label identifies the model, with its URL so multi-host summaries are unambiguous.
+func (sp *modelSpeed) label() string {
+	return sp.model + " @ " + sp.url
+}
@@ -0,0 +1,265 @@
+/*
+PURPOSE:
+  Statistical aggregation of repeated benchmark runs.
+  Turns a set of noisy single measurements into summary numbers.
+
+REQUIREMENTS:
+  User-specified:
+  - Mean, median, p95 and stddev of tokens/sec and duration.
+  - p50/p90/p95/p99 request latency for SRE-style reporting.
+  - Cross-fleet view of a model run on several backends: min/mean/max
+    tokens/sec and the fastest URL, shared by the run summary and report.
+
+  Implementation-discovered:
+  - Percentiles use linear interpolation between closest ranks.
+  - Sample (n-1) standard deviation; zero for a single sample.
+  - Fleet min/mean/max are over each URL's mean tokens/sec, so a backend
+    with more repeats doesn't outweigh the others.
+  - Fleet entries are per (model, prompt, config): pooling a ctx sweep or
+    several prompts would let a URL that only ran the cheap cases look fastest.
+
+ARCHITECTURE INTEGRATION:
+  - Used by: internal/engine (repeat runs, run summary), internal/output
+    (summary writer), internal/cli/report.go
+
+ERROR HANDLING:
+  - Empty input yields zero-valued stats.
+
+IMPLEMENTATION RULES:
+  - Pure functions; never mutate the caller's slice.
+
+USAGE:
+  s := model.ComputeStats([]float64{1, 2, 3})
+  l := model.NewLatencyStats([]time.Duration{...})
+  agg := model.NewAggregate(results)
+  fleet := model.NewFleetAggregates(results)
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/model/types.go
+
+MAINTENANCE:
+  - Add fields to Aggregate when new per-run metrics need summarizing.
+*/
+
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// Stats summarizes a distribution of samples.
+type Stats struct {
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	P95    float64 `json:"p95"`
+	StdDev float64 `json:"stddev"`
+}
+
+// LatencyStats holds request latency percentiles in seconds.
+type LatencyStats struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// Aggregate summarizes repeated runs of a single (model, url, config) tuple.
+type Aggregate struct {
+	Model           string                 `json:"model"`
+	URL             string                 `json:"url"`
+	Config          map[string]interface{} `json:"config"`
+	PromptName      string                 `json:"prompt_name,omitempty"`
+	Runs            int                    `json:"runs"`      // Samples included in the stats
+	Discarded       int                    `json:"discarded"` // Warmup runs excluded from the stats
+	TokensPerSecond Stats                  `json:"tokens_per_sec"`
+	DurationSeconds Stats                  `json:"duration_s"`
+	Latency         LatencyStats           `json:"latency_s"`
+}
+
+// FleetAggregate compares one model's speed, for one prompt and config,
+// across the backends that ran it.
+type FleetAggregate struct {
+	Model      string                 `json:"model"`
+	PromptName string                 `json:"prompt_name,omitempty"`
+	Config     map[string]interface{} `json:"config"`
+	URLs       int                    `json:"urls"` // Backends with at least one successful run
+	Runs       int                    `json:"runs"`
+	MinTPS     float64                `json:"min_tokens_per_sec"` // Slowest URL's mean
+	MeanTPS    float64                `json:"mean_tokens_per_sec"`
+	MaxTPS     float64                `json:"max_tokens_per_sec"` // Fastest URL's mean
+	FastestURL string                 `json:"fastest_url"`
+}
+
+// Percentile returns the p-th percentile (0-100) of samples using linear
+// interpolation between the closest ranks.
+func Percentile(samples []float64, p float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
+
+// ComputeStats calculates mean, median, p95 and sample standard deviation.
+func ComputeStats(samples []float64) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+
+	var sum float64
+	for _, v := range samples {
+		sum += v
+	}
+	mean := sum / float64(len(samples))
+
+	var stddev float64
+	if len(samples) > 1 {
+		var sq float64
+		for _, v := range samples {
+			sq += (v - mean) * (v - mean)
+		}
+		stddev = math.Sqrt(sq / float64(len(samples)-1))
+	}
+
+	return Stats{
+		Mean:   mean,
+		Median: Percentile(samples, 50),
+		P95:    Percentile(samples, 95),
+		StdDev: stddev,
+	}
+}
+
+// NewLatencyStats computes latency percentiles from a set of request durations.
+// With few samples the upper percentiles interpolate toward the slowest run.
+func NewLatencyStats(durations []time.Duration) LatencyStats {
+	seconds := make([]float64, len(durations))
+	for i, d := range durations {
+		seconds[i] = d.Seconds()
+	}
+	return LatencyStats{
+		P50: Percentile(seconds, 50),
+		P90: Percentile(seconds, 90),
+		P95: Percentile(seconds, 95),
+		P99: Percentile(seconds, 99),
+	}
+}
+
+// NewAggregate summarizes a set of results for the same (model, url, config).
+// discarded records how many warmup runs were excluded by the caller.
+func NewAggregate(results []Result, discarded int) Aggregate {
+	agg := Aggregate{Runs: len(results), Discarded: discarded}
+	if len(results) == 0 {
+		return agg
+	}
+
+	agg.Model = results[0].Model
+	agg.URL = results[0].URL
+	agg.Config = results[0].Config
+	agg.PromptName = results[0].PromptName
+
+	tps := make([]float64, 0, len(results))
+	durations := make([]float64, 0, len(results))
+	latencies := make([]time.Duration, 0, len(results))
+	for _, r := range results {
+		tps = append(tps, r.TokensPerSecond)
+		durations = append(durations, r.Duration.Seconds())
+		latencies = append(latencies, r.Duration)
+	}
+
+	agg.TokensPerSecond = ComputeStats(tps)
+	agg.DurationSeconds = ComputeStats(durations)
+	agg.Latency = NewLatencyStats(latencies)
+	return agg
+}
+
+// NewFleetAggregates groups successful results by (model, prompt, config)
+// across URLs, so each backend is compared on the same workload. Entries are
+// sorted by model, prompt and config. Failed runs and runs without a rate
+// (embeddings) are left out. Ties for fastest go to the alphabetically first URL.
+func NewFleetAggregates(results []Result) []FleetAggregate {
+	type group struct {
+		agg    FleetAggregate
+		config string
+		byURL  map[string][]float64 // url -> tokens/sec
+	}
+	groups := make(map[string]*group)
+	for _, r := range results {
+		if r.Error != "" || r.TokensPerSecond <= 0 {
+			continue
+		}
+		cfgBytes, _ := json.Marshal(r.Config) // sorted keys, same canonical form as --resume
+		key := r.Model + "\x00" + r.PromptName + "\x00" + string(cfgBytes)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				agg:    FleetAggregate{Model: r.Model, PromptName: r.PromptName, Config: r.Config},
+				config: string(cfgBytes),
+				byURL:  make(map[string][]float64),
+			}
+			groups[key] = g
+		}
+		g.byURL[r.URL] = append(g.byURL[r.URL], r.TokensPerSecond)
+	}
+
+	sorted := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.agg.Model != b.agg.Model {
+			return a.agg.Model < b.agg.Model
+		}
+		if a.agg.PromptName != b.agg.PromptName {
+			return a.agg.PromptName < b.agg.PromptName
+		}
+		return a.config < b.config
+	})
+
+	fleet := make([]FleetAggregate, 0, len(sorted))
+	for _, g := range sorted {
+		byURL := g.byURL
+		urls := make([]string, 0, len(byURL))
+		for url := range byURL {
+			urls = append(urls, url)
+		}
+		sort.Strings(urls)
+
+		agg := g.agg
+		agg.URLs = len(urls)
+		means := make([]float64, 0, len(urls))
+		for _, url := range urls {
+			mean := ComputeStats(byURL[url]).Mean
+			if len(means) == 0 || mean < agg.MinTPS {
+				agg.MinTPS = mean
+			}
+			if len(means) == 0 || mean > agg.MaxTPS {
+				agg.MaxTPS, agg.FastestURL = mean, url
+			}
+			means = append(means, mean)
+			agg.Runs += len(byURL[url])
+		}
+		agg.MeanTPS = ComputeStats(means).Mean
+		fleet = append(fleet, agg)
+	}
+	return fleet
+}
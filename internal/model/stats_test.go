@@ -0,0 +1,92 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []float64
+		p       float64
+		want    float64
+	}{
+		{name: "empty", samples: nil, p: 50, want: 0},
+		{name: "single", samples: []float64{7}, p: 99, want: 7},
+		{name: "min", samples: []float64{3, 1, 2}, p: 0, want: 1},
+		{name: "max", samples: []float64{3, 1, 2}, p: 100, want: 3},
+		{name: "median of odd", samples: []float64{3, 1, 2}, p: 50, want: 2},
+		{name: "interpolated", samples: []float64{1, 2, 3, 4}, p: 50, want: 2.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.samples, tt.p); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("Percentile(%v, %v) = %v, want %v", tt.samples, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLatencyStats(t *testing.T) {
+	// 1ms..100ms, in reverse so the input order doesn't matter
+	var durations []time.Duration
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	stats := NewLatencyStats(durations)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64 // seconds
+	}{
+		{name: "p50", got: stats.P50, want: 0.050},
+		{name: "p90", got: stats.P90, want: 0.090},
+		{name: "p95", got: stats.P95, want: 0.095},
+		{name: "p99", got: stats.P99, want: 0.099},
+	}
+	for _, tt := range tests {
+		// Linear interpolation lands within a millisecond of the nominal rank
+		if math.Abs(tt.got-tt.want) > 0.001 {
+			t.Errorf("%s = %.4fs, want about %.3fs", tt.name, tt.got, tt.want)
+		}
+	}
+	if !(stats.P50 < stats.P90 && stats.P90 < stats.P95 && stats.P95 < stats.P99) {
+		t.Errorf("percentiles not increasing: %+v", stats)
+	}
+}
+
+func TestNewFleetAggregatesGroupsByConfig(t *testing.T) {
+	small := map[string]interface{}{"num_ctx": 2048}
+	large := map[string]interface{}{"num_ctx": 8192}
+	results := []Result{
+		// gpu-a ran the whole ctx sweep; gpu-b only the cheap config
+		{Model: "llama3:8b", URL: "http://gpu-a", Config: small, TokensPerSecond: 100},
+		{Model: "llama3:8b", URL: "http://gpu-a", Config: large, TokensPerSecond: 40},
+		{Model: "llama3:8b", URL: "http://gpu-b", Config: small, TokensPerSecond: 80},
+		{Model: "llama3:8b", URL: "http://gpu-b", Config: small, PromptName: "long.txt", TokensPerSecond: 60},
+		{Model: "llama3:8b", URL: "http://gpu-b", Config: large, Error: "timeout"},
+	}
+
+	fleet := NewFleetAggregates(results)
+	if len(fleet) != 3 {
+		t.Fatalf("got %d fleet entries, want 3 (one per model/prompt/config): %+v", len(fleet), fleet)
+	}
+
+	// Sorted by model, prompt, then config: 2048 before 8192, unnamed prompt first
+	shared := fleet[0]
+	if shared.PromptName != "" || shared.Config["num_ctx"] != 2048 {
+		t.Fatalf("first entry = %+v, want the shared num_ctx 2048 config", shared)
+	}
+	if shared.URLs != 2 || shared.FastestURL != "http://gpu-a" || shared.MinTPS != 80 || shared.MaxTPS != 100 || shared.MeanTPS != 90 {
+		t.Errorf("shared config = %+v, want gpu-a fastest at 100 over gpu-b at 80", shared)
+	}
+	if sweep := fleet[1]; sweep.Config["num_ctx"] != 8192 || sweep.URLs != 1 || sweep.FastestURL != "http://gpu-a" {
+		t.Errorf("num_ctx 8192 entry = %+v, want gpu-a alone", sweep)
+	}
+	if prompt := fleet[2]; prompt.PromptName != "long.txt" || prompt.URLs != 1 || prompt.MaxTPS != 60 {
+		t.Errorf("long.txt entry = %+v, want gpu-b alone at 60", prompt)
+	}
+}
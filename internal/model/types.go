@@ -47,8 +47,10 @@ import (
 type Result struct {
 	Model              string                 `json:"model"`
 	URL                string                 `json:"url"`
-	Config             map[string]interface{} `json:"config"` // JSON object
+	Config             map[string]interface{} `json:"config"`                // JSON object
+	PromptName         string                 `json:"prompt_name,omitempty"` // Prompt file name (prompt_dir runs only)
 	Timestamp          time.Time              `json:"timestamp"`
+	Iteration          int                    `json:"iteration"` // 1-based repeat index (1 is the warmup when repeating)
 	Duration           time.Duration          `json:"duration"`
 	TotalDuration      time.Duration          `json:"total_duration"` // Server-side
 	LoadDuration       time.Duration          `json:"load_duration"`
@@ -56,14 +58,105 @@ type Result struct {
 	PromptEvalDuration time.Duration          `json:"prompt_eval_duration"`
 	EvalCount          int                    `json:"eval_count"`
 	EvalDuration       time.Duration          `json:"eval_duration"`
+	TimeToFirstToken   time.Duration          `json:"time_to_first_token"` // Streaming: first byte -> first token (excludes load)
 
 	// Resource Usage (from /api/ps)
 	MemoryUsage    int64   `json:"memory_usage_bytes"` // Total size
 	VRAMUsage      int64   `json:"vram_usage_bytes"`   // VRAM usage
 	VRAMPercentage float64 `json:"vram_percentage"`    // VRAM / Total
 
-	TokensGenerated int    `json:"tokens_generated"`
-	TokensReturned  int    `json:"tokens_returned"`
-	Response        string `json:"response,omitempty"` // Optional: full response text
-	Error           string `json:"error,omitempty"`    // If the run failed
+	TokensGenerated int     `json:"tokens_generated"`     // Real token count (eval_count)
+	ResponseRunes   int     `json:"response_runes"`       // Characters (Unicode code points) in the response text; not tokens
+	TokensPerSecond float64 `json:"tokens_per_sec"`       // EvalCount / EvalDuration
+	VectorDim       int     `json:"vector_dim,omitempty"` // Embedding size (embeddings endpoint only)
+	Response        string  `json:"response,omitempty"`   // Optional: full response text
+	Error           string  `json:"error,omitempty"`      // If the run failed
+
+	// ErrorKind categorizes a failure: network, timeout, api, placement_abort,
+	// server_5xx, client_4xx, context_exceeded (the prompt didn't fit num_ctx)
+	// or vram_headroom (skipped before loading; see max_vram_bytes).
+	// StatusCode is the HTTP status, if one was received.
+	ErrorKind  string `json:"error_kind,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+
+	// DoneReason is why generation stopped: "stop", or "length" when the
+	// num_predict (max_tokens) cap was hit
+	DoneReason string `json:"done_reason,omitempty"`
+
+	// Deterministic reports whether a repeat of the same request returned an
+	// identical response (verify_determinism only; nil when not checked)
+	Deterministic *bool `json:"deterministic,omitempty"`
+
+	// RunID identifies the invocation that produced the row, and Labels are the
+	// config's free-form labels (e.g. experiment=ctx-sweep), for pooling result files
+	RunID  string            `json:"run_id,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// EstCostPer1MTokens is a rough hardware cost of generating 1M tokens at
+	// this row's tokens_per_sec (cost_per_gpu_hour x gpu_count; 0 = not configured)
+	EstCostPer1MTokens float64 `json:"est_cost_per_1m_tokens,omitempty"`
+
+	// ImageCount and ImageBytes describe the images sent with the prompt
+	// (images config; bytes are the files on disk, not the base64 payload)
+	ImageCount int   `json:"image_count,omitempty"`
+	ImageBytes int64 `json:"image_bytes,omitempty"`
+
+	// ValidJSON reports whether the response parsed as JSON (format runs
+	// only; nil when not checked)
+	ValidJSON *bool `json:"valid_json,omitempty"`
+
+	// WarmStart reports whether the model was already loaded (per /api/ps)
+	// when the request was sent; false means it paid a cold load. nil when
+	// unknown (OpenAI-compatible backends, hosts without /api/ps)
+	WarmStart *bool `json:"warm_start,omitempty"`
+}
+
+// Message represents a single turn of a conversation sent to /api/chat.
+type Message struct {
+	Role    string `json:"role" yaml:"role"`       // "system", "user" or "assistant"
+	Content string `json:"content" yaml:"content"` // Message text
+}
+
+// ModelInfo is one installed model as listed by a backend.
+// OpenAI-compatible backends report names only; Size and ModifiedAt stay zero.
+type ModelInfo struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"` // Bytes on disk
+	ModifiedAt time.Time `json:"modified_at"`
+
+	// Details is the "details" object /api/tags reports per model
+	Details ModelDetails `json:"details"`
+}
+
+// ModelDetails describes a model's build, as reported by Ollama.
+type ModelDetails struct {
+	Family            string `json:"family"`
+	ParameterSize     string `json:"parameter_size"`     // e.g. "8.0B"
+	QuantizationLevel string `json:"quantization_level"` // e.g. "Q4_K_M"
+	Format            string `json:"format"`             // e.g. "gguf"
+}
+
+// InventoryEntry is one deployed model on one backend, as written by the
+// inventory command.
+type InventoryEntry struct {
+	URL           string    `json:"url"`
+	Model         string    `json:"model"`
+	Size          int64     `json:"size"` // Bytes on disk (0 = unknown)
+	ModifiedAt    time.Time `json:"modified_at"`
+	Family        string    `json:"family,omitempty"`
+	ParameterSize string    `json:"parameter_size,omitempty"`
+	Quantization  string    `json:"quantization,omitempty"`
+	ContextLength int       `json:"context_length,omitempty"` // Trained context (/api/show)
+}
+
+// HostInfo describes a backend at the time of a run, so results can be
+// correlated with server upgrades and hardware.
+type HostInfo struct {
+	URL            string    `json:"url"`
+	Protocol       string    `json:"protocol"`
+	Version        string    `json:"version,omitempty"`       // Ollama server version (/api/version)
+	LoadedModels   []string  `json:"loaded_models,omitempty"` // Models resident when the run started (/api/ps)
+	VRAMInUseBytes int64     `json:"vram_in_use_bytes"`       // Sum of size_vram over loaded models
+	CollectedAt    time.Time `json:"collected_at"`
+	Error          string    `json:"error,omitempty"` // Why some info is missing
 }
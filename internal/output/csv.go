@@ -11,6 +11,13 @@ REQUIREMENTS:
   Implementation-discovered:
   - Needs to create file if not exists, or truncate if new run?
   - Original script used `unlink` then `open("w")`, implying overwrite.
+  - Delimiter and duration precision are configurable (csv_delimiter,
+    csv_precision); defaults reproduce the original format exactly.
+  - Append mode (csv_append) accumulates runs in one file; the header is
+    written only when the file is empty, detected by its size before writing.
+  - Appending to a file whose header differs (older column set, another
+    delimiter) would misalign every new row, so it is refused instead.
+  - New columns go at the end so positional readers of older files still line up.
 
 ARCHITECTURE INTEGRATION:
   - Called by: internal/engine
@@ -18,6 +25,7 @@ ARCHITECTURE INTEGRATION:
 
 ERROR HANDLING:
   - Returns error on file creation or write failure.
+  - NewCSVWriterAppend returns error if the existing header does not match.
 
 IMPLEMENTATION RULES:
   - Use encoding/csv.
@@ -25,7 +33,8 @@ IMPLEMENTATION RULES:
   - Use Mutex if concurrent writes are expected (Engine might be parallel).
 
 USAGE:
-  w, err := output.NewCSVWriter("results.csv")
+  w, err := output.NewCSVWriter("results.csv", output.DefaultCSVOptions)
+  w, err := output.NewCSVWriterAppend("history.csv", opts) // nightly accumulation
   w.Write(result)
   w.Close()
 
@@ -46,6 +55,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"sync"
 
 	"github.com/daryltucker/forest-runner/internal/model"
@@ -55,37 +65,107 @@ import (
 type CSVWriter struct {
 	file   *os.File
 	writer *csv.Writer
+	opts   CSVOptions
 	mu     sync.Mutex
 }
 
+// CSVOptions controls CSV formatting.
+type CSVOptions struct {
+	Delimiter rune // Field separator, e.g. ';' for German-locale spreadsheets
+	Precision int  // Decimals for the *_s duration columns
+}
+
+// DefaultCSVOptions matches the historical output: comma-separated, 4-decimal durations.
+var DefaultCSVOptions = CSVOptions{Delimiter: ',', Precision: 4}
+
+// csvHeader names the CSV columns.
+// gen_tokens is the server's token count (eval_count); response_runes is the
+// character length of the response text and is not a token count.
+var csvHeader = []string{
+	"model", "url", "config", "prompt_name", "timestamp", "iteration", "client_duration_s",
+	"total_duration_s", "load_duration_s", "prompt_eval_s", "eval_duration_s", "ttft_s",
+	"prompt_tokens", "gen_tokens", "response_runes", "tokens_per_sec",
+	"vram_usage_mb", "vram_gpu_pct", "vector_dim",
+	"response", "error", "error_kind", "status_code",
+	"run_id", "labels", "est_cost_per_1m_tokens", "image_count", "image_bytes", "valid_json", "warm_start",
+}
+
 // NewCSVWriter creates a new CSVWriter.
 // It overwrites the file if it exists.
-func NewCSVWriter(path string) (*CSVWriter, error) {
+func NewCSVWriter(path string, opts CSVOptions) (ResultWriter, error) {
 	f, err := os.Create(path)
 	if err != nil {
 		return nil, err
 	}
+	return newCSVWriter(f, opts, true)
+}
 
-	w := csv.NewWriter(f)
+// NewCSVFileWriter writes CSV results with a header row to an already-open,
+// empty file (one reserved by a Sink).
+func NewCSVFileWriter(f *os.File, opts CSVOptions) (ResultWriter, error) {
+	return newCSVWriter(f, opts, true)
+}
 
-	// Write Header
-	// Write Header
-	header := []string{
-		"model", "url", "config", "timestamp", "client_duration_s",
-		"total_duration_s", "load_duration_s", "prompt_eval_s", "eval_duration_s",
-		"prompt_tokens", "gen_tokens", "tokens_returned",
-		"vram_usage_mb", "vram_gpu_pct",
-		"response", "error",
+// NewCSVWriterAppend opens path for appending, creating it if needed.
+// The header is only written when the file is new or empty, so one file can
+// accumulate rows across many runs. A non-empty file must start with the
+// current header in the same delimiter, or rows would land under the wrong columns.
+func NewCSVWriterAppend(path string, opts CSVOptions) (ResultWriter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
 	}
-	if err := w.Write(header); err != nil {
+	info, err := f.Stat()
+	if err != nil {
 		f.Close()
 		return nil, err
 	}
-	w.Flush()
+	if info.Size() > 0 {
+		if err := checkCSVHeader(f, opts); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("cannot append to %s: %w; move it aside or disable csv_append", path, err)
+		}
+	}
+	return newCSVWriter(f, opts, info.Size() == 0)
+}
+
+// checkCSVHeader reads the first record of f and compares it to csvHeader.
+// Writes still go to the end: f is opened with O_APPEND.
+func checkCSVHeader(f *os.File, opts CSVOptions) error {
+	r := csv.NewReader(f)
+	r.Comma = opts.Delimiter
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("unreadable header: %w", err)
+	}
+	if len(header) != len(csvHeader) {
+		return fmt.Errorf("existing header has %d columns, this version writes %d", len(header), len(csvHeader))
+	}
+	for i, col := range header {
+		if col != csvHeader[i] {
+			return fmt.Errorf("existing header column %d is %q, expected %q", i+1, col, csvHeader[i])
+		}
+	}
+	return nil
+}
+
+func newCSVWriter(f *os.File, opts CSVOptions, writeHeader bool) (ResultWriter, error) {
+	w := csv.NewWriter(f)
+	w.Comma = opts.Delimiter
+
+	if writeHeader {
+		if err := w.Write(csvHeader); err != nil {
+			f.Close()
+			return nil, err
+		}
+		w.Flush()
+	}
 
 	return &CSVWriter{
 		file:   f,
 		writer: w,
+		opts:   opts,
 	}, nil
 }
 
@@ -102,19 +182,33 @@ func (cw *CSVWriter) Write(r model.Result) error {
 		r.Model,
 		r.URL,
 		configStr,
+		r.PromptName,
 		r.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
-		fmt.Sprintf("%.4f", r.Duration.Seconds()),
-		fmt.Sprintf("%.4f", r.TotalDuration.Seconds()),
-		fmt.Sprintf("%.4f", r.LoadDuration.Seconds()),
-		fmt.Sprintf("%.4f", r.PromptEvalDuration.Seconds()),
-		fmt.Sprintf("%.4f", r.EvalDuration.Seconds()),
+		fmt.Sprintf("%d", r.Iteration),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.Duration.Seconds()),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.TotalDuration.Seconds()),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.LoadDuration.Seconds()),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.PromptEvalDuration.Seconds()),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.EvalDuration.Seconds()),
+		fmt.Sprintf("%.*f", cw.opts.Precision, r.TimeToFirstToken.Seconds()),
 		fmt.Sprintf("%d", r.PromptEvalCount),
 		fmt.Sprintf("%d", r.TokensGenerated),
-		fmt.Sprintf("%d", r.TokensReturned),
+		fmt.Sprintf("%d", r.ResponseRunes),
+		fmt.Sprintf("%.2f", r.TokensPerSecond),
 		fmt.Sprintf("%.2f", float64(r.VRAMUsage)/1024/1024), // MB
 		fmt.Sprintf("%.1f", r.VRAMPercentage),
+		fmt.Sprintf("%d", r.VectorDim),
 		r.Response,
 		r.Error,
+		r.ErrorKind,
+		fmt.Sprintf("%d", r.StatusCode),
+		r.RunID,
+		labelsJSON(r.Labels),
+		fmt.Sprintf("%.4f", r.EstCostPer1MTokens),
+		fmt.Sprintf("%d", r.ImageCount),
+		fmt.Sprintf("%d", r.ImageBytes),
+		optionalBool(r.ValidJSON),
+		optionalBool(r.WarmStart),
 	}
 
 	if err := cw.writer.Write(record); err != nil {
@@ -124,6 +218,23 @@ func (cw *CSVWriter) Write(r model.Result) error {
 	return cw.writer.Error()
 }
 
+// labelsJSON renders result labels as a JSON object, or "" when there are none.
+func labelsJSON(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(labels)
+	return string(b)
+}
+
+// optionalBool renders a checked flag as "true"/"false", or "" when unchecked.
+func optionalBool(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
 // Close closes the underlying file.
 func (cw *CSVWriter) Close() error {
 	cw.writer.Flush()
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+func TestCSVAppendKeepsOneHeader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.csv")
+	for run := 0; run < 2; run++ {
+		w, err := NewCSVWriterAppend(path, DefaultCSVOptions)
+		if err != nil {
+			t.Fatalf("run %d: %v", run+1, err)
+		}
+		if err := w.Write(model.Result{Model: "llama3:8b"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want a header and two rows:\n%s", len(lines), data)
+	}
+	if !strings.HasPrefix(lines[0], csvHeader[0]+",") || strings.HasPrefix(lines[2], csvHeader[0]+",") {
+		t.Fatalf("header not written exactly once:\n%s", data)
+	}
+}
+
+func TestCSVAppendRefusesHeaderMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{name: "older columns", header: strings.Join(csvHeader[:len(csvHeader)-1], ",") + "\n"},
+		{name: "renamed column", header: "modell," + strings.Join(csvHeader[1:], ",") + "\n"},
+		{name: "other delimiter", header: strings.Join(csvHeader, ";") + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "history.csv")
+			if err := os.WriteFile(path, []byte(tt.header), 0644); err != nil {
+				t.Fatal(err)
+			}
+			w, err := NewCSVWriterAppend(path, DefaultCSVOptions)
+			if err == nil {
+				w.Close()
+				t.Fatal("appended to a file with a mismatched header")
+			}
+			data, _ := os.ReadFile(path)
+			if string(data) != tt.header {
+				t.Fatalf("file was modified after refusing: %q", data)
+			}
+		})
+	}
+}
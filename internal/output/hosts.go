@@ -0,0 +1,76 @@
+/*
+PURPOSE:
+  Writes the per-URL host information collected during a run to <stem>_hosts.json.
+
+REQUIREMENTS:
+  User-specified:
+  - Store host info (Ollama version, VRAM) per URL alongside the results.
+
+  Implementation-discovered:
+  - A single JSON object keyed by URL; small enough to write once at the end.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run)
+  - Consumes: internal/model.HostInfo
+
+ERROR HANDLING:
+  - Returns error on marshal or write failure.
+
+IMPLEMENTATION RULES:
+  - Thread-safe recording; hosts are collected by concurrent URL workers.
+
+USAGE:
+  hosts := output.NewHostRecorder()
+  hosts.Record(info)
+  err := hosts.WriteFile("model_results_hosts.json")
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/engine/hostinfo.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// HostRecorder collects HostInfo per URL.
+type HostRecorder struct {
+	hosts map[string]model.HostInfo
+	mu    sync.Mutex
+}
+
+// NewHostRecorder creates an empty HostRecorder.
+func NewHostRecorder() *HostRecorder {
+	return &HostRecorder{hosts: make(map[string]model.HostInfo)}
+}
+
+// Record stores the info for its URL, replacing any earlier entry.
+func (h *HostRecorder) Record(info model.HostInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.hosts[info.URL] = info
+}
+
+// WriteFile writes every recorded host as indented JSON keyed by URL.
+func (h *HostRecorder) WriteFile(path string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := json.MarshalIndent(h.hosts, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
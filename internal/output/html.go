@@ -0,0 +1,258 @@
+/*
+PURPOSE:
+  Writes a self-contained HTML report with a tokens/sec bar chart.
+  Meant for sharing results with people who won't open a CSV.
+
+REQUIREMENTS:
+  User-specified:
+  - Self-contained: inline JS charting, no CDN.
+  - Bars grouped by model, colored by URL (multi-backend comparison).
+  - Data templated as a JSON blob read by the inline script.
+
+  Implementation-discovered:
+  - Repeat runs are averaged per (model, url) so each bar is one number.
+  - Failed results are listed in the table but excluded from the chart.
+  - html/template JSON-encodes values placed in <script>, which also escapes "</script>".
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine (when "html" is listed in outputs)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error on file creation; Close() returns template/write errors.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+  - No external assets of any kind; the file must render offline.
+
+USAGE:
+  w, err := output.NewHTMLWriter("model_results.html")
+  w.Write(result)
+  w.Close() // report is rendered here
+
+SELF-HEALING INSTRUCTIONS:
+  - Blank chart: open the browser console; the script reads the "data" constant.
+
+RELATED FILES:
+  - internal/output/markdown.go
+  - internal/output/writer.go
+
+MAINTENANCE:
+  - Keep the script dependency-free (plain SVG via DOM APIs).
+*/
+
+package output
+
+import (
+	"bufio"
+	"html/template"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// htmlBar is one chart bar: mean tokens/sec for a model on a backend.
+type htmlBar struct {
+	Model string  `json:"model"`
+	URL   string  `json:"url"`
+	TPS   float64 `json:"tps"`
+	Runs  int     `json:"runs"`
+}
+
+// htmlReport is the data handed to the template.
+type htmlReport struct {
+	Started string
+	URLs    []string
+	Bars    []htmlBar
+	Results []model.Result
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"config": mdConfig,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Forest Runner Results</title>
+<style>
+  body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+  h1 { margin-bottom: 0.2rem; }
+  .meta { color: #666; margin-bottom: 1.5rem; }
+  .legend span { display: inline-block; margin-right: 1rem; }
+  .legend i { display: inline-block; width: 0.8rem; height: 0.8rem; margin-right: 0.3rem; vertical-align: middle; }
+  table { border-collapse: collapse; margin-top: 2rem; font-size: 0.85rem; }
+  th, td { border: 1px solid #ddd; padding: 0.3rem 0.6rem; text-align: left; }
+  td.num { text-align: right; }
+  tr.error td { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Forest Runner Results</h1>
+<div class="meta">Run started {{.Started}} &middot; {{len .URLs}} backend(s) &middot; {{len .Results}} result(s)</div>
+
+<h2>Tokens/sec by model</h2>
+<div class="legend" id="legend"></div>
+<div id="chart"></div>
+
+<h2>All results</h2>
+<table>
+<tr><th>Model</th><th>URL</th><th>Config</th><th>Run</th><th>Tk/s</th><th>Duration (s)</th><th>Load (s)</th><th>VRAM%</th><th>Error</th></tr>
+{{- range .Results}}
+<tr{{if .Error}} class="error"{{end}}><td>{{.Model}}</td><td>{{.URL}}</td><td>{{config .Config}}</td><td class="num">{{.Iteration}}</td><td class="num">{{printf "%.1f" .TokensPerSecond}}</td><td class="num">{{printf "%.3f" .Duration.Seconds}}</td><td class="num">{{printf "%.3f" .LoadDuration.Seconds}}</td><td class="num">{{printf "%.1f" .VRAMPercentage}}</td><td>{{.Error}}</td></tr>
+{{- end}}
+</table>
+
+<script>
+const data = {{.Bars}};
+const urls = {{.URLs}};
+const palette = ["#4e79a7", "#f28e2b", "#59a14f", "#e15759", "#76b7b2", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"];
+const color = u => palette[urls.indexOf(u) % palette.length];
+const svgNS = "http://www.w3.org/2000/svg";
+
+function el(name, attrs, text) {
+  const e = document.createElementNS(svgNS, name);
+  for (const k in attrs) e.setAttribute(k, attrs[k]);
+  if (text !== undefined) e.textContent = text;
+  return e;
+}
+
+const legend = document.getElementById("legend");
+urls.forEach(u => {
+  const s = document.createElement("span");
+  const i = document.createElement("i");
+  i.style.background = color(u);
+  s.appendChild(i);
+  s.appendChild(document.createTextNode(u));
+  legend.appendChild(s);
+});
+
+const models = [...new Set(data.map(d => d.model))];
+const max = Math.max(1, ...data.map(d => d.tps));
+const barW = 18, groupGap = 24, chartH = 300, top = 20, left = 50, bottom = 120;
+let x = left;
+const width = left + models.reduce((w, m) => w + data.filter(d => d.model === m).length * barW + groupGap, 0);
+const svg = el("svg", {width: width, height: top + chartH + bottom});
+
+for (let i = 0; i <= 4; i++) {
+  const v = max * i / 4, y = top + chartH - chartH * i / 4;
+  svg.appendChild(el("line", {x1: left, x2: width, y1: y, y2: y, stroke: "#eee"}));
+  svg.appendChild(el("text", {x: left - 6, y: y + 4, "text-anchor": "end", "font-size": 10}, v.toFixed(0)));
+}
+
+models.forEach(m => {
+  const bars = data.filter(d => d.model === m);
+  const start = x;
+  bars.forEach(d => {
+    const h = chartH * d.tps / max;
+    const r = el("rect", {x: x, y: top + chartH - h, width: barW - 2, height: h, fill: color(d.url)});
+    r.appendChild(el("title", {}, d.model + " @ " + d.url + ": " + d.tps.toFixed(1) + " tok/s (" + d.runs + " run(s))"));
+    svg.appendChild(r);
+    x += barW;
+  });
+  const cx = (start + x) / 2, ly = top + chartH + 12;
+  svg.appendChild(el("text", {x: cx, y: ly, "font-size": 11, "text-anchor": "end", transform: "rotate(-40 " + cx + " " + ly + ")"}, m));
+  x += groupGap;
+});
+
+document.getElementById("chart").appendChild(svg);
+</script>
+</body>
+</html>
+`))
+
+// HTMLWriter buffers results and renders a standalone HTML report on Close.
+type HTMLWriter struct {
+	file    *os.File
+	started time.Time
+	results []model.Result
+	mu      sync.Mutex
+}
+
+// NewHTMLWriter creates a new HTMLWriter.
+func NewHTMLWriter(path string) (ResultWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTMLFileWriter(f)
+}
+
+// NewHTMLFileWriter writes the HTML report to an already-open, empty file
+// (one reserved by a Sink).
+func NewHTMLFileWriter(f *os.File) (ResultWriter, error) {
+	return &HTMLWriter{
+		file:    f,
+		started: time.Now(),
+	}, nil
+}
+
+// Write buffers a single result.
+func (hw *HTMLWriter) Write(r model.Result) error {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	hw.results = append(hw.results, r)
+	return nil
+}
+
+// Close renders the report and closes the underlying file.
+func (hw *HTMLWriter) Close() error {
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	report := htmlReport{
+		Started: hw.started.Format(time.RFC3339),
+		Results: hw.results,
+	}
+
+	// Average successful runs per (model, url), preserving first-seen order
+	type key struct{ model, url string }
+	sums := make(map[key]*htmlBar)
+	var order []key
+	seenURL := make(map[string]bool)
+	for _, r := range hw.results {
+		if !seenURL[r.URL] {
+			seenURL[r.URL] = true
+			report.URLs = append(report.URLs, r.URL)
+		}
+		if r.Error != "" {
+			continue
+		}
+		k := key{r.Model, r.URL}
+		b, ok := sums[k]
+		if !ok {
+			b = &htmlBar{Model: r.Model, URL: r.URL}
+			sums[k] = b
+			order = append(order, k)
+		}
+		b.TPS += r.TokensPerSecond
+		b.Runs++
+	}
+	sort.Strings(report.URLs)
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].model != order[j].model {
+			return order[i].model < order[j].model
+		}
+		return order[i].url < order[j].url
+	})
+	report.Bars = make([]htmlBar, 0, len(order))
+	for _, k := range order {
+		b := *sums[k]
+		b.TPS /= float64(b.Runs)
+		report.Bars = append(report.Bars, b)
+	}
+
+	w := bufio.NewWriter(hw.file)
+	if err := htmlTemplate.Execute(w, report); err != nil {
+		hw.file.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		hw.file.Close()
+		return err
+	}
+	return hw.file.Close()
+}
@@ -0,0 +1,186 @@
+/*
+PURPOSE:
+  Writes benchmark results as InfluxDB line protocol, one point per result,
+  for long-term dashboards without a custom exporter.
+
+REQUIREMENTS:
+  User-specified:
+  - Measurement ollama_benchmark, tagged by model and url, with tokens_per_sec,
+    vram_pct and duration_s fields and the result timestamp.
+  - Escape tag values (spaces, commas, equals signs in model names).
+  - Selected through outputs ("influx").
+
+  Implementation-discovered:
+  - A file rather than a push: ingest with `influx write -f <stem>.lp` or
+    POST it to /api/v2/write; the benchmark never depends on the database
+    being reachable.
+  - prompt and config are tags too, so dashboards can split by them; config
+    is its JSON form (sorted keys), so equal configs share a tag value.
+  - Empty tag values are not allowed by the protocol, so those tags are left out.
+  - Failed runs are written with success=false and the error fields, so error
+    rates can be graphed next to speed.
+  - Non-finite floats (NaN/Inf) are rejected by InfluxDB and are skipped.
+  - Result labels become tags (sorted; ones named like a built-in tag are
+    dropped). run_id is a string field rather than a tag, so every run doesn't
+    start new series.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (registered with the Sink)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error on file creation or write failure.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+  - One line per Write, written straight to the file (no buffering).
+
+USAGE:
+  w, err := output.NewInfluxWriter("model_results.lp")
+  w.Write(result)
+  w.Close()
+
+SELF-HEALING INSTRUCTIONS:
+  - "unable to parse" on import: a new tag or string field is missing escaping.
+
+RELATED FILES:
+  - internal/model/types.go
+
+MAINTENANCE:
+  - Add fields in influxLine when Result gains metrics worth graphing.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// InfluxMeasurement is the measurement name of every point.
+const InfluxMeasurement = "ollama_benchmark"
+
+// InfluxWriter writes results to a line-protocol file.
+type InfluxWriter struct {
+	file *os.File
+	mu   sync.Mutex
+}
+
+// NewInfluxWriter creates (or truncates) a line-protocol file.
+func NewInfluxWriter(path string) (ResultWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewInfluxFileWriter(f)
+}
+
+// NewInfluxFileWriter writes line protocol to an already-open, empty file
+// (one reserved by a Sink).
+func NewInfluxFileWriter(f *os.File) (ResultWriter, error) {
+	return &InfluxWriter{file: f}, nil
+}
+
+// Write appends one point for the result.
+func (iw *InfluxWriter) Write(r model.Result) error {
+	line := influxLine(r)
+
+	iw.mu.Lock()
+	defer iw.mu.Unlock()
+	_, err := iw.file.WriteString(line)
+	return err
+}
+
+// Close closes the underlying file.
+func (iw *InfluxWriter) Close() error {
+	return iw.file.Close()
+}
+
+// influxLine renders a result as one line-protocol point, newline-terminated.
+func influxLine(r model.Result) string {
+	var b strings.Builder
+	b.WriteString(InfluxMeasurement)
+
+	tag := func(key, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString("," + key + "=" + influxTagEscaper.Replace(value))
+	}
+	tag("model", r.Model)
+	tag("url", r.URL)
+	tag("prompt", r.PromptName)
+	if len(r.Config) > 0 {
+		if cfg, err := json.Marshal(r.Config); err == nil {
+			tag("config", string(cfg))
+		}
+	}
+	labels := make([]string, 0, len(r.Labels))
+	for k := range r.Labels {
+		if k != "" && !influxBuiltinTags[k] {
+			labels = append(labels, k)
+		}
+	}
+	sort.Strings(labels)
+	for _, k := range labels {
+		tag(influxTagEscaper.Replace(k), r.Labels[k])
+	}
+
+	sep := " "
+	field := func(key, value string) {
+		b.WriteString(sep + key + "=" + value)
+		sep = ","
+	}
+	float := func(key string, v float64) {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			field(key, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	integer := func(key string, v int64) {
+		field(key, strconv.FormatInt(v, 10)+"i")
+	}
+	str := func(key, v string) {
+		if v != "" {
+			field(key, `"`+influxStringEscaper.Replace(v)+`"`)
+		}
+	}
+
+	field("success", strconv.FormatBool(r.Error == ""))
+	float("duration_s", r.Duration.Seconds())
+	float("tokens_per_sec", r.TokensPerSecond)
+	float("vram_pct", r.VRAMPercentage)
+	float("ttft_s", r.TimeToFirstToken.Seconds())
+	float("load_duration_s", r.LoadDuration.Seconds())
+	integer("tokens_generated", int64(r.TokensGenerated))
+	integer("prompt_eval_count", int64(r.PromptEvalCount))
+	integer("iteration", int64(r.Iteration))
+	str("error", r.Error)
+	str("error_kind", r.ErrorKind)
+	str("run_id", r.RunID)
+	if r.StatusCode != 0 {
+		integer("status_code", int64(r.StatusCode))
+	}
+
+	if !r.Timestamp.IsZero() {
+		b.WriteString(" " + strconv.FormatInt(r.Timestamp.UnixNano(), 10))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// influxBuiltinTags are the tags every point carries; labels can't replace them.
+var influxBuiltinTags = map[string]bool{"model": true, "url": true, "prompt": true, "config": true}
+
+// influxTagEscaper escapes tag keys and values; line protocol has no escape for
+// newlines, so they become spaces.
+var influxTagEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `, "\n", `\ `, "\r", "")
+
+// influxStringEscaper escapes string field values (inside double quotes).
+var influxStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
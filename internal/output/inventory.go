@@ -0,0 +1,94 @@
+/*
+PURPOSE:
+  Writes a fleet model inventory (url, model, size, family, quantization,
+  context length) as JSON or CSV.
+
+REQUIREMENTS:
+  User-specified:
+  - JSON/CSV snapshot of what is deployed where.
+
+  Implementation-discovered:
+  - JSON is one indented array: the inventory is small and read by people
+    as often as by scripts.
+  - CSV sizes are raw bytes so spreadsheets can sort them.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/cli/inventory.go
+  - Consumes: internal/model.InventoryEntry
+
+ERROR HANDLING:
+  - Returns error on encode or write failure.
+
+IMPLEMENTATION RULES:
+  - Writes to any io.Writer (file or stdout).
+
+USAGE:
+  err := output.WriteInventoryJSON(os.Stdout, entries)
+  err := output.WriteInventoryCSV(f, entries)
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/engine/inventory.go
+
+MAINTENANCE:
+  - Keep inventoryHeader in step with InventoryEntry.
+*/
+
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// inventoryHeader names the inventory CSV columns.
+var inventoryHeader = []string{"url", "model", "size_bytes", "modified_at", "family", "parameter_size", "quantization", "context_length"}
+
+// WriteInventoryJSON writes entries as an indented JSON array.
+func WriteInventoryJSON(w io.Writer, entries []model.InventoryEntry) error {
+	if entries == nil {
+		entries = []model.InventoryEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteInventoryCSV writes entries as CSV with a header row.
+func WriteInventoryCSV(w io.Writer, entries []model.InventoryEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(inventoryHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		modified := ""
+		if !e.ModifiedAt.IsZero() {
+			modified = e.ModifiedAt.Format(time.RFC3339)
+		}
+		contextLength := ""
+		if e.ContextLength > 0 {
+			contextLength = strconv.Itoa(e.ContextLength)
+		}
+		if err := cw.Write([]string{
+			e.URL,
+			e.Model,
+			strconv.FormatInt(e.Size, 10),
+			modified,
+			e.Family,
+			e.ParameterSize,
+			e.Quantization,
+			contextLength,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
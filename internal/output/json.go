@@ -9,6 +9,9 @@ REQUIREMENTS:
 
   Implementation-discovered:
   - JSON Lines is better for streaming/logging than a single large array (append-friendly).
+  - json_indent pretty-prints each result with a blank line between them, for
+    eyeballing a few results. That is no longer one object per line, so it is
+    debug-only; ReadResults still reads it, other NDJSON tools won't.
 
 ARCHITECTURE INTEGRATION:
   - Called by: internal/engine
@@ -50,28 +53,60 @@ import (
 type JSONWriter struct {
 	file    *os.File
 	encoder *json.Encoder
+	pretty  bool
 	mu      sync.Mutex
 }
 
 // NewJSONWriter creates a new JSONWriter.
-func NewJSONWriter(path string) (*JSONWriter, error) {
+func NewJSONWriter(path string) (ResultWriter, error) {
 	f, err := os.Create(path)
 	if err != nil {
 		return nil, err
 	}
+	return NewJSONFileWriter(f)
+}
 
+// NewJSONFileWriter writes NDJSON results to an already-open, empty file
+// (one reserved by a Sink).
+func NewJSONFileWriter(f *os.File) (ResultWriter, error) {
 	return &JSONWriter{
 		file:    f,
 		encoder: json.NewEncoder(f),
 	}, nil
 }
 
-// Write writes a single result as a JSON line.
+// NewPrettyJSONWriter creates a JSONWriter that indents each result and
+// separates them with a blank line. The output is not strict NDJSON.
+func NewPrettyJSONWriter(path string) (ResultWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrettyJSONFileWriter(f)
+}
+
+// NewPrettyJSONFileWriter is NewPrettyJSONWriter for an already-open, empty file.
+func NewPrettyJSONFileWriter(f *os.File) (ResultWriter, error) {
+	w, _ := NewJSONFileWriter(f)
+	jw := w.(*JSONWriter)
+	jw.encoder.SetIndent("", "  ")
+	jw.pretty = true
+	return jw, nil
+}
+
+// Write writes a single result as a JSON line (an indented block when pretty).
 func (jw *JSONWriter) Write(r model.Result) error {
 	jw.mu.Lock()
 	defer jw.mu.Unlock()
 
-	return jw.encoder.Encode(r)
+	if err := jw.encoder.Encode(r); err != nil {
+		return err
+	}
+	if jw.pretty {
+		_, err := jw.file.WriteString("\n")
+		return err
+	}
+	return nil
 }
 
 // Close closes the underlying file.
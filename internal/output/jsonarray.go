@@ -0,0 +1,112 @@
+/*
+PURPOSE:
+  Writes benchmark results as a single JSON array, for tools that can't read
+  JSON Lines (jq without -s, some BI importers).
+
+REQUIREMENTS:
+  User-specified:
+  - "json-array" output: "[", comma-separated results, "]".
+  - Flush as it goes and leave valid JSON if the run is interrupted.
+  - NDJSON ("json") stays the default.
+
+  Implementation-discovered:
+  - The closing "]" is written after every result and overwritten by the
+    next one, so the file is a valid array at every point, including after
+    a crash; Close has nothing left to finish.
+  - Named <stem>_array.json so it can sit next to the NDJSON <stem>.json.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (registered with the Sink)
+  - Read by: ReadResults (detects the leading "[")
+
+ERROR HANDLING:
+  - Returns error on file creation or write failure.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+  - Each Write is one WriteAt at the tracked offset; no buffering.
+
+USAGE:
+  w, err := output.NewJSONArrayWriter("results_array.json")
+  w.Write(result)
+  w.Close()
+
+SELF-HEALING INSTRUCTIONS:
+  - Truncated array: the process died mid-write; the last element is lost
+    and everything before it still parses after trimming to the last "},".
+
+RELATED FILES:
+  - internal/output/json.go
+  - internal/output/reader.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// arrayClose ends the array; it is rewritten after each element.
+const arrayClose = "\n]\n"
+
+// JSONArrayWriter writes results to a file holding one JSON array.
+type JSONArrayWriter struct {
+	file   *os.File
+	offset int64 // Where the next element (or separator) is written
+	count  int
+	mu     sync.Mutex
+}
+
+// NewJSONArrayWriter creates the file holding an empty array.
+func NewJSONArrayWriter(path string) (ResultWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONArrayFileWriter(f)
+}
+
+// NewJSONArrayFileWriter writes a JSON array of results to an already-open,
+// empty file (one reserved by a Sink).
+func NewJSONArrayFileWriter(f *os.File) (ResultWriter, error) {
+	if _, err := f.WriteString("[" + arrayClose); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &JSONArrayWriter{file: f, offset: 1}, nil
+}
+
+// Write appends a result to the array, keeping the file valid JSON.
+func (aw *JSONArrayWriter) Write(r model.Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	sep := "\n  "
+	if aw.count > 0 {
+		sep = ",\n  "
+	}
+	chunk := append([]byte(sep), data...)
+	if _, err := aw.file.WriteAt(append(chunk, arrayClose...), aw.offset); err != nil {
+		return err
+	}
+	aw.offset += int64(len(chunk))
+	aw.count++
+	return nil
+}
+
+// Close closes the underlying file; the array is already terminated.
+func (aw *JSONArrayWriter) Close() error {
+	return aw.file.Close()
+}
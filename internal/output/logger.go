@@ -29,14 +29,16 @@ RELATED FILES:
   - All.
 
 MAINTENANCE:
-  - Configurable log levels?
+  - Levels/format are configured via NewLogger from root persistent flags.
 */
 
 package output
 
 import (
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 var Logger *slog.Logger
@@ -51,3 +53,22 @@ func init() {
 func SetLogger(l *slog.Logger) {
 	Logger = l
 }
+
+// NewLogger builds a logger for the given level (debug, info, warn, error)
+// and format (text, json). Output always goes to stdout.
+func NewLogger(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q (expected debug, info, warn or error)", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stdout, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stdout, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (expected text or json)", format)
+	}
+}
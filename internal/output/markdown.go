@@ -0,0 +1,180 @@
+/*
+PURPOSE:
+  Writes benchmark results as a Markdown table for PRs and wikis.
+
+REQUIREMENTS:
+  User-specified:
+  - Buffer results in memory and write a sorted table once, on Close().
+  - Columns: model, config, prompt, tok/s, durations, VRAM%.
+  - Header line with the run timestamp and target URLs.
+
+  Implementation-discovered:
+  - Target URLs are collected from the results themselves, keeping the
+    constructor signature identical to the other writers.
+  - The file is created up front so path errors surface before the run starts.
+  - Pipes in cell values (e.g. error text) must be escaped.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine (when "markdown" is listed in outputs)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error on file creation; Close() returns the first write error.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+  - Not crash resilient by design: an aborted process writes nothing.
+    Interrupted runs (SIGINT) still close writers, so partial tables are written.
+
+USAGE:
+  w, err := output.NewMarkdownWriter("model_results.md")
+  w.Write(result)
+  w.Close() // table is written here
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/output/writer.go
+  - internal/cli/report.go
+
+MAINTENANCE:
+  - Keep columns roughly in line with the CSV writer.
+*/
+
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// MarkdownWriter buffers results and renders them as a Markdown table on Close.
+type MarkdownWriter struct {
+	file    *os.File
+	started time.Time
+	results []model.Result
+	mu      sync.Mutex
+}
+
+// NewMarkdownWriter creates a new MarkdownWriter.
+func NewMarkdownWriter(path string) (ResultWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewMarkdownFileWriter(f)
+}
+
+// NewMarkdownFileWriter writes the Markdown report to an already-open, empty
+// file (one reserved by a Sink).
+func NewMarkdownFileWriter(f *os.File) (ResultWriter, error) {
+	return &MarkdownWriter{
+		file:    f,
+		started: time.Now(),
+	}, nil
+}
+
+// Write buffers a single result.
+func (mw *MarkdownWriter) Write(r model.Result) error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	mw.results = append(mw.results, r)
+	return nil
+}
+
+// Close renders the table and closes the underlying file.
+func (mw *MarkdownWriter) Close() error {
+	mw.mu.Lock()
+	defer mw.mu.Unlock()
+
+	sort.SliceStable(mw.results, func(i, j int) bool {
+		a, b := mw.results[i], mw.results[j]
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		if ca, cb := mdConfig(a.Config), mdConfig(b.Config); ca != cb {
+			return ca < cb
+		}
+		if a.PromptName != b.PromptName {
+			return a.PromptName < b.PromptName
+		}
+		if a.URL != b.URL {
+			return a.URL < b.URL
+		}
+		return a.Iteration < b.Iteration
+	})
+
+	var urls []string
+	seen := make(map[string]bool)
+	for _, r := range mw.results {
+		if !seen[r.URL] {
+			seen[r.URL] = true
+			urls = append(urls, r.URL)
+		}
+	}
+	sort.Strings(urls)
+
+	w := bufio.NewWriter(mw.file)
+	fmt.Fprintf(w, "# Forest Runner Results\n\n")
+	fmt.Fprintf(w, "Run started %s against %s\n\n", mw.started.Format(time.RFC3339), mdJoinURLs(urls))
+	fmt.Fprintln(w, "| Model | URL | Config | Prompt | Run | Tk/s | Duration (s) | Load (s) | Eval (s) | TTFT (s) | VRAM% | Error |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | --- |")
+	for _, r := range mw.results {
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %d | %.1f | %.3f | %.3f | %.3f | %.3f | %.1f | %s |\n",
+			mdEscape(r.Model),
+			mdEscape(r.URL),
+			mdEscape(mdConfig(r.Config)),
+			mdEscape(r.PromptName),
+			r.Iteration,
+			r.TokensPerSecond,
+			r.Duration.Seconds(),
+			r.LoadDuration.Seconds(),
+			r.EvalDuration.Seconds(),
+			r.TimeToFirstToken.Seconds(),
+			r.VRAMPercentage,
+			mdEscape(r.Error),
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		mw.file.Close()
+		return err
+	}
+	return mw.file.Close()
+}
+
+// mdConfig renders an inference config as compact JSON (sorted keys).
+func mdConfig(cfg map[string]interface{}) string {
+	if len(cfg) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(cfg)
+	return string(data)
+}
+
+// mdEscape makes a value safe for a single Markdown table cell.
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func mdJoinURLs(urls []string) string {
+	if len(urls) == 0 {
+		return "no backends"
+	}
+	quoted := make([]string, len(urls))
+	for i, u := range urls {
+		quoted[i] = "`" + u + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,176 @@
+/*
+PURPOSE:
+  Reads benchmark results back from JSON Lines files (or json-array files).
+  The inverse of JSONWriter, used by resume and analysis commands.
+
+REQUIREMENTS:
+  User-specified:
+  - Analyze and resume from previous NDJSON result files.
+
+  Implementation-discovered:
+  - Responses can be long; the scanner buffer must allow large lines.
+  - Interrupted runs can leave a truncated final line.
+  - A run directory (run_dir_layout) can be passed in place of the file; its
+    single results .json is used, ignoring the summary, hosts and run.meta.json.
+  - A file starting with a lone "{" line is json_indent output and is decoded
+    as a stream of objects.
+  - A file starting with "[" is a json-array file and is decoded whole. In a
+    run directory the JSON Lines file is preferred; the array is skipped.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine (resume), internal/cli (report, compare)
+  - Produces: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error if the file cannot be opened or read.
+  - Returns error if a directory holds zero or several results files.
+  - Skips (with a warning) lines that are not valid results.
+
+IMPLEMENTATION RULES:
+  - Read-only.
+
+USAGE:
+  results, err := output.ReadResults("model_results.json")
+  results, err := output.ReadResults("results/run-0003")
+
+SELF-HEALING INSTRUCTIONS:
+  - None specific.
+
+RELATED FILES:
+  - internal/output/json.go
+
+MAINTENANCE:
+  - Keep in sync with the JSONWriter format.
+*/
+
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// ReadResults loads every valid result from a JSON Lines file or a run directory.
+func ReadResults(path string) ([]model.Result, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		if path, err = resultsFileIn(path); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open results file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	// json-array output is one array rather than a line per result
+	br := bufio.NewReader(f)
+	if isJSONArray(br) {
+		var results []model.Result
+		if err := json.NewDecoder(br).Decode(&results); err != nil {
+			return nil, fmt.Errorf("failed to read results array %s: %w", path, err)
+		}
+		return results, nil
+	}
+	if isPrettyJSON(br) {
+		return readPrettyJSON(br, path), nil
+	}
+
+	var results []model.Result
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // responses can be long
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var r model.Result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			Logger.Warn("Skipping invalid line in results file", "path", path, "line", line, "error", err)
+			continue
+		}
+		results = append(results, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read results file %s: %w", path, err)
+	}
+
+	return results, nil
+}
+
+// isJSONArray reports whether the first non-space byte in br is "[".
+func isJSONArray(br *bufio.Reader) bool {
+	for i := 1; ; i++ {
+		b, err := br.Peek(i)
+		if err != nil {
+			return false
+		}
+		switch b[i-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isPrettyJSON reports whether br starts with a lone "{" line, as written by
+// the json output with json_indent.
+func isPrettyJSON(br *bufio.Reader) bool {
+	b, _ := br.Peek(2)
+	return string(b) == "{\n"
+}
+
+// readPrettyJSON decodes indented results one after another. A truncated last
+// result (interrupted run) ends the read with a warning, like a bad NDJSON line.
+func readPrettyJSON(br *bufio.Reader, path string) []model.Result {
+	var results []model.Result
+	dec := json.NewDecoder(br)
+	for {
+		var r model.Result
+		if err := dec.Decode(&r); err != nil {
+			if err != io.EOF {
+				Logger.Warn("Stopped at invalid result in results file", "path", path, "after", len(results), "error", err)
+			}
+			return results
+		}
+		results = append(results, r)
+	}
+}
+
+// resultsFileIn finds the results JSON Lines file inside a run directory.
+func resultsFileIn(dir string) (string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+	var found []string
+	for _, m := range matches {
+		base := filepath.Base(m)
+		if base == RunMetaFile || strings.HasSuffix(base, SummarySuffix) || strings.HasSuffix(base, HostsSuffix) || strings.HasSuffix(base, JSONArraySuffix) {
+			continue
+		}
+		found = append(found, m)
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("no results .json file in %s (was json in outputs?)", dir)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("several results files in %s; pass one explicitly: %s", dir, strings.Join(found, ", "))
+	}
+}
@@ -0,0 +1,68 @@
+/*
+PURPOSE:
+  Writes run.meta.json into a run directory (run_dir_layout).
+  Records when a run happened, against which backends, and with what config.
+
+REQUIREMENTS:
+  User-specified:
+  - Timestamp, tool version, config snapshot and URL list alongside each run's results.
+  - run_id and labels, matching the ones stamped on every result row.
+
+  Implementation-discovered:
+  - The config snapshot uses YAML key names and is redacted (header values)
+    by the caller via config.Snapshot().
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run)
+  - Read by: humans and scripts; ReadResults skips it when given a run directory.
+
+ERROR HANDLING:
+  - Returns error on marshal or write failure.
+
+IMPLEMENTATION RULES:
+  - Written once, before any results, so aborted runs still carry metadata.
+
+USAGE:
+  err := output.WriteRunMeta(dir, output.RunMeta{Timestamp: time.Now(), URLs: cfg.URLs, Config: snap})
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/output/sink.go
+  - internal/output/reader.go
+
+MAINTENANCE:
+  - Add fields to RunMeta rather than changing existing ones; scripts read them.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunMetaFile is the metadata file name inside a run directory.
+const RunMetaFile = "run.meta.json"
+
+// RunMeta describes a single run.
+type RunMeta struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Version   string                 `json:"version"`
+	RunID     string                 `json:"run_id"`
+	Labels    map[string]string      `json:"labels,omitempty"`
+	URLs      []string               `json:"urls"`
+	Config    map[string]interface{} `json:"config"`
+}
+
+// WriteRunMeta writes meta as indented JSON to <dir>/run.meta.json.
+func WriteRunMeta(dir string, meta RunMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, RunMetaFile), append(data, '\n'), 0644)
+}
@@ -0,0 +1,390 @@
+/*
+PURPOSE:
+  Owns a run's output location: creates the output directory, reserves the
+  run number once, and hands every writer and artifact a path with the same
+  run suffix (model_results-3.csv pairs with model_results-3.json).
+  With run_dir_layout, reserves a numbered run-NNNN/ directory instead.
+
+REQUIREMENTS:
+  User-specified:
+  - Never clobber prior results, even with two processes writing to the same dir.
+  - Avoid probing .1, .2, ... one Stat at a time on every run.
+  - One component for directory creation and versioning, so new formats
+    register instead of duplicating it.
+
+  Implementation-discovered:
+  - Reservation uses O_CREATE|O_EXCL, so the check and the create are one atomic step.
+  - Every file of a run is claimed together; if any already exists the whole
+    number is released and the next one is tried.
+  - Reserved files stay open and are handed to writers with an OpenFile
+    constructor, so they are not opened twice. SQLite needs a path, so formats
+    with only Open get the handle closed and re-open by path.
+  - Run directories are reserved with os.Mkdir, which fails if the directory
+    exists, so two processes never share one.
+  - Registration comes first and Open reserves everything at once, because the
+    run number depends on every suffix the run will write.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/runner.go (Run)
+  - Opens: any ResultWriter constructor (CSV, JSON, JSON array, SQLite, Markdown, HTML, Influx)
+
+ERROR HANDLING:
+  - Open returns error if the output directory cannot be created or read, a
+    file cannot be reserved for reasons other than already existing, or a
+    writer fails to open. Writers opened before the failure, and reserved
+    handles not yet given to a writer, are closed.
+
+IMPLEMENTATION RULES:
+  - One directory listing to find the highest existing run number.
+  - Register/Reserve before Open; Path after Open.
+  - Safe for concurrent use.
+
+USAGE:
+  sink := output.NewSink("results", "model_results", false)
+  sink.Register(output.SinkFormat{Name: "json", Suffix: ".json", OpenFile: output.NewJSONFileWriter})
+  sink.Reserve("hosts", output.HostsSuffix)
+  err := sink.Open()
+  defer sink.Close()
+  hostsPath := sink.Path(output.HostsSuffix)
+
+SELF-HEALING INSTRUCTIONS:
+  - Empty result files left behind mean a run failed between reservation and writing.
+
+RELATED FILES:
+  - internal/engine/runner.go
+  - internal/output/writer.go
+  - internal/output/runmeta.go
+
+MAINTENANCE:
+  - Add new output file suffixes to knownSuffixes.
+*/
+
+package output
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Suffixes of the per-run artifacts that are not result writers.
+const (
+	// SummarySuffix names the aggregated stats file written for repeat runs.
+	SummarySuffix = "_summary.json"
+	// HostsSuffix names the per-URL host info file (Ollama version, VRAM in use).
+	HostsSuffix = "_hosts.json"
+	// JSONArraySuffix names the json-array results file, kept apart from the NDJSON <stem>.json.
+	JSONArraySuffix = "_array.json"
+	// ConfigUsedSuffix names the effective-config snapshot written next to a run's results.
+	ConfigUsedSuffix = ".config.used.yaml"
+)
+
+// knownSuffixes are every suffix a run may write. All of them are considered
+// when picking a run number, so numbering stays monotonic even if the enabled
+// formats change between runs.
+var knownSuffixes = []string{".csv", ".json", ".db", ".md", ".html", ".lp", JSONArraySuffix, SummarySuffix, HostsSuffix, ConfigUsedSuffix}
+
+// runDirPrefix names run directories: run-0001, run-0002, ...
+const runDirPrefix = "run-"
+
+// SinkFormat is a result format registered with a Sink. OpenFile, when set,
+// is preferred over Open and receives the reserved, empty file.
+type SinkFormat struct {
+	Name        string                                  // Format name, logged as results_<name>
+	Suffix      string                                  // Appended to the run base path
+	Open        func(path string) (ResultWriter, error) // Writer constructor by path
+	OpenFile    func(f *os.File) (ResultWriter, error)  // Writer constructor for the reserved handle
+	Unversioned bool                                    // Always <dir>/<stem><suffix>, growing across runs
+}
+
+// sinkEntry is a registered writer or artifact and, once opened, its path.
+type sinkEntry struct {
+	name   string
+	suffix string
+	format *SinkFormat
+	path   string
+}
+
+// Sink owns the output files of one run.
+type Sink struct {
+	outputDir string
+	stem      string
+	runDir    bool
+
+	mu      sync.Mutex
+	entries []sinkEntry
+	dir     string // Directory holding this run's files
+	base    string // Path prefix shared by every versioned file
+	writers []ResultWriter
+	opened  bool
+}
+
+// NewSink prepares a sink for <outputDir>/<stem>*; with runDir each run gets
+// its own run-NNNN/ directory. Nothing touches the disk until Open.
+func NewSink(outputDir, stem string, runDir bool) *Sink {
+	return &Sink{outputDir: outputDir, stem: stem, runDir: runDir}
+}
+
+// Register adds a result format; Open reserves its file and opens the writer.
+func (s *Sink) Register(f SinkFormat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, sinkEntry{name: "results_" + f.Name, suffix: f.Suffix, format: &f})
+}
+
+// Reserve adds a non-writer artifact (summary, host info, ...) whose file
+// shares the run's suffix. name labels its path in Paths.
+func (s *Sink) Reserve(name, suffix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, sinkEntry{name: name, suffix: suffix})
+}
+
+// Open creates the output (and run) directory, reserves one run number for
+// every registered suffix and opens the registered writers.
+func (s *Sink) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.opened {
+		return fmt.Errorf("output sink already open")
+	}
+
+	if err := os.MkdirAll(s.outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", s.outputDir, err)
+	}
+	s.dir = s.outputDir
+	if s.runDir {
+		dir, err := reserveRunDir(s.outputDir)
+		if err != nil {
+			return fmt.Errorf("failed to create run directory in %s: %w", s.outputDir, err)
+		}
+		s.dir = dir
+	}
+
+	var suffixes []string
+	for _, e := range s.entries {
+		if e.format == nil || !e.format.Unversioned {
+			suffixes = append(suffixes, e.suffix)
+		}
+	}
+	base, files, err := reserveRunBase(s.dir, s.stem, suffixes)
+	if err != nil {
+		return fmt.Errorf("failed to reserve output files in %s: %w", s.dir, err)
+	}
+	defer closeFiles(files) // Whatever no writer took
+	s.base = base
+	s.opened = true
+
+	for i := range s.entries {
+		e := &s.entries[i]
+		e.path = base + e.suffix
+		if e.format == nil {
+			continue
+		}
+		if e.format.Unversioned {
+			e.path = filepath.Join(s.outputDir, s.stem) + e.suffix
+		}
+
+		var w ResultWriter
+		if f := files[e.suffix]; f != nil && e.format.OpenFile != nil && !e.format.Unversioned {
+			delete(files, e.suffix)
+			w, err = e.format.OpenFile(f)
+		} else {
+			if f != nil {
+				delete(files, e.suffix)
+				f.Close()
+			}
+			w, err = e.format.Open(e.path)
+		}
+		if err != nil {
+			s.closeWriters()
+			return fmt.Errorf("failed to init %s writer at %s: %w", e.format.Name, e.path, err)
+		}
+		s.writers = append(s.writers, w)
+	}
+	return nil
+}
+
+// Dir is the directory holding this run's files: the output directory, or
+// the run-NNNN/ directory with run_dir_layout.
+func (s *Sink) Dir() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dir
+}
+
+// Base is the path prefix shared by this run's versioned files, for
+// artifacts named at run time (e.g. per-model token timings).
+func (s *Sink) Base() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.base
+}
+
+// Path returns the reserved path for suffix (base + suffix).
+func (s *Sink) Path(suffix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.entries {
+		if e.suffix == suffix && e.path != "" {
+			return e.path
+		}
+	}
+	return s.base + suffix
+}
+
+// Writers returns the opened result writers in registration order.
+func (s *Sink) Writers() []ResultWriter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ResultWriter(nil), s.writers...)
+}
+
+// Paths lists name/path pairs of every registered file, ready for slog.
+func (s *Sink) Paths() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var paths []any
+	for _, e := range s.entries {
+		paths = append(paths, e.name, e.path)
+	}
+	return paths
+}
+
+// Close closes every opened writer and returns the first error.
+func (s *Sink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeWriters()
+}
+
+// closeWriters closes and forgets the opened writers. Callers hold s.mu.
+func (s *Sink) closeWriters() error {
+	var first error
+	for _, w := range s.writers {
+		if err := w.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	s.writers = nil
+	return first
+}
+
+// reserveRunBase returns the path prefix shared by every output of this run and
+// atomically creates an empty file for each of the given suffixes, returning
+// the open handles by suffix.
+// The first run uses <dir>/<stem>; later runs use <stem>-1, <stem>-2, ... so that
+// results-3.csv always pairs with results-3.json.
+func reserveRunBase(dir, stem string, suffixes []string) (string, map[string]*os.File, error) {
+	prefix := filepath.Join(dir, stem)
+	parent, name := filepath.Split(prefix)
+	if parent == "" {
+		parent = "."
+	}
+
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Start after the highest run number already on disk
+	next := 0
+	for _, e := range entries {
+		for _, suffix := range knownSuffixes {
+			rest, ok := strings.CutSuffix(e.Name(), suffix)
+			if !ok {
+				continue
+			}
+			if rest == name {
+				next = max(next, 1)
+			} else if num, ok := strings.CutPrefix(rest, name+"-"); ok {
+				if n, err := strconv.Atoi(num); err == nil && n >= next {
+					next = n + 1
+				}
+			}
+		}
+	}
+
+	for i := next; ; i++ {
+		base := prefix
+		if i > 0 {
+			base = fmt.Sprintf("%s-%d", prefix, i)
+		}
+
+		files, err := claimFiles(base, suffixes)
+		if err != nil {
+			return "", nil, err
+		}
+		if files != nil {
+			return base, files, nil
+		}
+	}
+}
+
+// reserveRunDir atomically creates the next numbered run directory under parent.
+func reserveRunDir(parent string) (string, error) {
+	entries, err := os.ReadDir(parent)
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	for _, e := range entries {
+		if num, ok := strings.CutPrefix(e.Name(), runDirPrefix); ok && e.IsDir() {
+			if n, err := strconv.Atoi(num); err == nil && n >= next {
+				next = n + 1
+			}
+		}
+	}
+
+	for i := next; ; i++ {
+		dir := filepath.Join(parent, fmt.Sprintf("%s%04d", runDirPrefix, i))
+		err := os.Mkdir(dir, 0755)
+		if err == nil {
+			return dir, nil
+		}
+		if !os.IsExist(err) {
+			return "", err
+		}
+	}
+}
+
+// claimFiles exclusively creates base+suffix for every suffix and returns the
+// open handles by suffix. If any file already exists, files created by this
+// call are closed and removed and a nil map is returned.
+func claimFiles(base string, suffixes []string) (map[string]*os.File, error) {
+	files := make(map[string]*os.File, len(suffixes))
+	release := func() {
+		for _, f := range files {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}
+
+	for _, suffix := range suffixes {
+		if _, ok := files[suffix]; ok {
+			continue
+		}
+		f, err := os.OpenFile(base+suffix, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			release()
+			if os.IsExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		files[suffix] = f
+	}
+	return files, nil
+}
+
+// closeFiles closes reserved handles that were not given to a writer; the
+// empty files stay on disk for the artifacts written later by path.
+func closeFiles(files map[string]*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
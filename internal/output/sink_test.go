@@ -0,0 +1,170 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// touch creates empty files under dir.
+func touch(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSinkSkipsExistingRuns(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, dir, "model_results.csv", "model_results-1.csv", "model_results-2.csv",
+		"model_results-3.csv", "model_results-4.csv", "model_results-5.csv")
+
+	var handed string
+	sink := NewSink(dir, "model_results", false)
+	sink.Register(SinkFormat{Name: "json", Suffix: ".json", OpenFile: func(f *os.File) (ResultWriter, error) {
+		handed = f.Name()
+		return NewJSONFileWriter(f)
+	}})
+	if err := sink.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(dir, "model_results-6.json")
+	if got := sink.Path(".json"); got != want {
+		t.Fatalf("Path(.json) = %s, want %s", got, want)
+	}
+	if handed != want {
+		t.Fatalf("writer got handle for %q, want the reserved %s", handed, want)
+	}
+
+	if err := sink.Writers()[0].Write(model.Result{Model: "llama3:8b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"model":"llama3:8b"`) {
+		t.Fatalf("reserved file does not hold the result: %q", data)
+	}
+}
+
+func TestClaimFilesReleasesOnConflict(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "model_results-6")
+	touch(t, dir, "model_results-6.json")
+
+	files, err := claimFiles(base, []string{".csv", ".json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != nil {
+		closeFiles(files)
+		t.Fatal("claimFiles claimed a run whose .json already exists")
+	}
+	if _, err := os.Stat(base + ".csv"); !os.IsNotExist(err) {
+		t.Fatalf("partially claimed %s.csv was not removed (stat err %v)", base, err)
+	}
+}
+
+func TestSinkRunNumberSpansSuffixes(t *testing.T) {
+	dir := t.TempDir()
+	// A previous run wrote only SQLite and a summary; this one writes JSON
+	touch(t, dir, "model_results.db", "model_results-4.db", "model_results-2_summary.json", "other-9.json")
+
+	sink := NewSink(dir, "model_results", false)
+	sink.Register(SinkFormat{Name: "json", Suffix: ".json", OpenFile: NewJSONFileWriter})
+	sink.Reserve("hosts", HostsSuffix)
+	if err := sink.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	base := filepath.Join(dir, "model_results-5")
+	if got := sink.Base(); got != base {
+		t.Fatalf("Base() = %s, want %s", got, base)
+	}
+	for _, suffix := range []string{".json", HostsSuffix} {
+		if got := sink.Path(suffix); got != base+suffix {
+			t.Errorf("Path(%s) = %s, want %s", suffix, got, base+suffix)
+		}
+		if _, err := os.Stat(base + suffix); err != nil {
+			t.Errorf("%s was not reserved: %v", base+suffix, err)
+		}
+	}
+}
+
+func TestSinkRunDirLayout(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"run-0001", "run-0003"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	touch(t, dir, "run-0007") // A file, not a run directory
+
+	sink := NewSink(dir, "model_results", true)
+	sink.Register(SinkFormat{Name: "json", Suffix: ".json", OpenFile: NewJSONFileWriter})
+	if err := sink.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	runDir := filepath.Join(dir, "run-0004")
+	if got := sink.Dir(); got != runDir {
+		t.Fatalf("Dir() = %s, want %s", got, runDir)
+	}
+	// Each run directory holds one run, so its files carry no run number
+	if got, want := sink.Path(".json"), filepath.Join(runDir, "model_results.json"); got != want {
+		t.Fatalf("Path(.json) = %s, want %s", got, want)
+	}
+}
+
+// closeTracker is a ResultWriter that records whether it was closed.
+type closeTracker struct{ closed bool }
+
+func (c *closeTracker) Write(model.Result) error { return nil }
+func (c *closeTracker) Close() error             { c.closed = true; return nil }
+
+func TestSinkOpenFailureClosesWriters(t *testing.T) {
+	dir := t.TempDir()
+	first := &closeTracker{}
+	var handed *os.File
+
+	sink := NewSink(dir, "model_results", false)
+	sink.Register(SinkFormat{Name: "csv", Suffix: ".csv", OpenFile: func(f *os.File) (ResultWriter, error) {
+		f.Close()
+		return first, nil
+	}})
+	sink.Register(SinkFormat{Name: "broken", Suffix: ".lp", OpenFile: func(f *os.File) (ResultWriter, error) {
+		handed = f
+		f.Close()
+		return nil, os.ErrPermission
+	}})
+	sink.Reserve("hosts", HostsSuffix)
+
+	err := sink.Open()
+	if err == nil {
+		t.Fatal("Open succeeded with a failing writer")
+	}
+	if !strings.Contains(err.Error(), "broken") {
+		t.Errorf("error %q does not name the failing format", err)
+	}
+	if handed == nil {
+		t.Fatal("failing writer was not given its reserved handle")
+	}
+	if !first.closed {
+		t.Error("writer opened before the failure was not closed")
+	}
+	if n := len(sink.Writers()); n != 0 {
+		t.Errorf("Writers() = %d writers after a failed Open, want 0", n)
+	}
+}
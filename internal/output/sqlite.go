@@ -0,0 +1,218 @@
+/*
+PURPOSE:
+  Writes benchmark results to a SQLite database for ad-hoc SQL queries.
+  e.g. SELECT model, avg(tokens_per_sec) FROM results GROUP BY model;
+
+REQUIREMENTS:
+  User-specified:
+  - Same Write/Close contract as CSVWriter.
+  - A `results` table whose columns mirror model.Result; config stored as JSON text.
+  - Persist every write immediately (crash resilience).
+
+  Implementation-discovered:
+  - modernc.org/sqlite is pure Go, so CGO_ENABLED=0 release builds keep working.
+  - Durations are stored as REAL seconds (matching the CSV columns) so SQL math is trivial.
+  - Each INSERT runs in its own implicit transaction, which is durable on return.
+  - Columns added since the first release are appended with ALTER TABLE when
+    an older database is reused (CREATE TABLE IF NOT EXISTS won't add them).
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine (when "sqlite" is listed in outputs)
+  - Consumes: internal/model.Result
+
+ERROR HANDLING:
+  - Returns error on open, schema creation, or insert failure.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+  - Single connection; SQLite serializes writers anyway.
+
+USAGE:
+  w, err := output.NewSQLiteWriter("model_results.db")
+  w.Write(result)
+  w.Close()
+
+SELF-HEALING INSTRUCTIONS:
+  - "database is locked": another process holds the file; use a fresh output path.
+
+RELATED FILES:
+  - internal/model/types.go
+  - internal/output/csv.go
+
+MAINTENANCE:
+  - Add a column to the schema and the INSERT when Result gains a field, and
+    list it in sqliteAddedColumns.
+*/
+
+package output
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sync"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS results (
+	id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+	model               TEXT NOT NULL,
+	url                 TEXT NOT NULL,
+	config              TEXT,
+	prompt_name         TEXT,
+	timestamp           TEXT,
+	iteration           INTEGER,
+	duration_s          REAL,
+	total_duration_s    REAL,
+	load_duration_s     REAL,
+	prompt_eval_count   INTEGER,
+	prompt_eval_s       REAL,
+	eval_count          INTEGER,
+	eval_duration_s     REAL,
+	ttft_s              REAL,
+	memory_usage_bytes  INTEGER,
+	vram_usage_bytes    INTEGER,
+	vram_gpu_pct        REAL,
+	tokens_generated    INTEGER,
+	response_runes      INTEGER,
+	tokens_per_sec      REAL,
+	vector_dim          INTEGER,
+	response            TEXT,
+	error               TEXT,
+	error_kind          TEXT,
+	status_code         INTEGER,
+	run_id              TEXT,
+	labels              TEXT
+)`
+
+// sqliteAddedColumns were added after the first release; databases created
+// before then get them via ALTER TABLE so appending keeps working.
+var sqliteAddedColumns = []struct{ name, decl string }{
+	{"error_kind", "TEXT"},
+	{"status_code", "INTEGER"},
+	{"run_id", "TEXT"},
+	{"labels", "TEXT"},
+}
+
+const sqliteInsert = `INSERT INTO results (
+	model, url, config, prompt_name, timestamp, iteration,
+	duration_s, total_duration_s, load_duration_s,
+	prompt_eval_count, prompt_eval_s, eval_count, eval_duration_s, ttft_s,
+	memory_usage_bytes, vram_usage_bytes, vram_gpu_pct,
+	tokens_generated, response_runes, tokens_per_sec, vector_dim,
+	response, error, error_kind, status_code, run_id, labels
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// SQLiteWriter handles writing results to a SQLite database.
+type SQLiteWriter struct {
+	db   *sql.DB
+	stmt *sql.Stmt
+	mu   sync.Mutex
+}
+
+// NewSQLiteWriter opens (or creates) the database and ensures the results table exists.
+func NewSQLiteWriter(path string) (ResultWriter, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := addMissingColumns(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	stmt, err := db.Prepare(sqliteInsert)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteWriter{
+		db:   db,
+		stmt: stmt,
+	}, nil
+}
+
+// addMissingColumns upgrades a results table created by an older release.
+func addMissingColumns(db *sql.DB) error {
+	rows, err := db.Query("SELECT name FROM pragma_table_info('results')")
+	if err != nil {
+		return err
+	}
+	have := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		have[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, col := range sqliteAddedColumns {
+		if have[col.name] {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE results ADD COLUMN " + col.name + " " + col.decl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write inserts a single result row.
+// It is thread-safe.
+func (sw *SQLiteWriter) Write(r model.Result) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	configBytes, _ := json.Marshal(r.Config)
+
+	_, err := sw.stmt.Exec(
+		r.Model,
+		r.URL,
+		string(configBytes),
+		r.PromptName,
+		r.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
+		r.Iteration,
+		r.Duration.Seconds(),
+		r.TotalDuration.Seconds(),
+		r.LoadDuration.Seconds(),
+		r.PromptEvalCount,
+		r.PromptEvalDuration.Seconds(),
+		r.EvalCount,
+		r.EvalDuration.Seconds(),
+		r.TimeToFirstToken.Seconds(),
+		r.MemoryUsage,
+		r.VRAMUsage,
+		r.VRAMPercentage,
+		r.TokensGenerated,
+		r.ResponseRunes,
+		r.TokensPerSecond,
+		r.VectorDim,
+		r.Response,
+		r.Error,
+		r.ErrorKind,
+		r.StatusCode,
+		r.RunID,
+		labelsJSON(r.Labels),
+	)
+	return err
+}
+
+// Close closes the prepared statement and the database.
+func (sw *SQLiteWriter) Close() error {
+	sw.stmt.Close()
+	return sw.db.Close()
+}
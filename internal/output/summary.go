@@ -0,0 +1,80 @@
+/*
+PURPOSE:
+  Writes aggregated repeat-run statistics to a JSON Lines file.
+  One line per (model, url, config) tuple.
+
+REQUIREMENTS:
+  User-specified:
+  - Summary rows (mean/median/p95/stddev) in a separate *_summary.json file.
+
+  Implementation-discovered:
+  - Same NDJSON layout as JSONWriter so vecq/jq tooling works unchanged.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine
+  - Consumes: internal/model.Aggregate
+
+ERROR HANDLING:
+  - Returns error on file creation or write failure.
+
+IMPLEMENTATION RULES:
+  - Thread-safe.
+
+USAGE:
+  w, err := output.NewSummaryWriter("model_results_summary.json")
+  w.Write(agg)
+  w.Close()
+
+SELF-HEALING INSTRUCTIONS:
+  - None specific.
+
+RELATED FILES:
+  - internal/model/stats.go
+  - internal/output/json.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/daryltucker/forest-runner/internal/model"
+)
+
+// SummaryWriter handles writing aggregates to a JSON Lines file.
+type SummaryWriter struct {
+	file    *os.File
+	encoder *json.Encoder
+	mu      sync.Mutex
+}
+
+// NewSummaryWriter creates a new SummaryWriter.
+func NewSummaryWriter(path string) (*SummaryWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SummaryWriter{
+		file:    f,
+		encoder: json.NewEncoder(f),
+	}, nil
+}
+
+// Write writes a single aggregate as a JSON line.
+func (sw *SummaryWriter) Write(a model.Aggregate) error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+
+	return sw.encoder.Encode(a)
+}
+
+// Close closes the underlying file.
+func (sw *SummaryWriter) Close() error {
+	return sw.file.Close()
+}
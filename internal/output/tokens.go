@@ -0,0 +1,119 @@
+/*
+PURPOSE:
+  Writes per-token arrival timing from streamed responses to sidecar files,
+  for inter-token latency and jitter analysis (e.g. spotting server GC pauses).
+
+REQUIREMENTS:
+  User-specified:
+  - token_timings writes <model>-tokens.jsonl with {token_index, delta_ms}.
+  - Streaming only; off by default because it produces a lot of data.
+
+  Implementation-discovered:
+  - Files are named after the run base (model_results-3.tiny_1b-tokens.jsonl)
+    so they pair with the run's results; ':' and '/' in model names become '_'.
+  - One file per model across URLs; every line carries the url, and lines of
+    one stream are written together under the lock.
+  - token_index 0's delta is the time from the first response byte to the
+    first token; later deltas are the gap since the previous token.
+
+ARCHITECTURE INTEGRATION:
+  - Called by: internal/engine/client.go (processStream), runner.go (Run)
+
+ERROR HANDLING:
+  - Returns error if a file cannot be opened or written.
+
+IMPLEMENTATION RULES:
+  - Thread-safe; files are opened lazily and appended to.
+
+USAGE:
+  tl := output.NewTokenLog(base)
+  err := tl.Write("llama3:8b", "http://gpu1:11434", deltas)
+  tl.Close()
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/engine/client.go
+
+MAINTENANCE:
+  - None.
+*/
+
+package output
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenLogSuffix ends every per-model token timing file.
+const TokenLogSuffix = "-tokens.jsonl"
+
+// tokenTiming is one line of a token timing file.
+type tokenTiming struct {
+	URL        string  `json:"url"`
+	TokenIndex int     `json:"token_index"`
+	DeltaMS    float64 `json:"delta_ms"`
+}
+
+// TokenLog appends streamed token timings to one file per model.
+type TokenLog struct {
+	base  string
+	mu    sync.Mutex
+	files map[string]*os.File
+}
+
+// NewTokenLog writes files named <base>.<model>-tokens.jsonl.
+func NewTokenLog(base string) *TokenLog {
+	return &TokenLog{base: base, files: make(map[string]*os.File)}
+}
+
+// TokenLogPath is the token timing file for modelName under base.
+func TokenLogPath(base, modelName string) string {
+	return base + "." + strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(modelName) + TokenLogSuffix
+}
+
+// Write appends one stream's token deltas for modelName on url.
+func (t *TokenLog) Write(modelName, url string, deltas []time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, ok := t.files[modelName]
+	if !ok {
+		var err error
+		f, err = os.OpenFile(TokenLogPath(t.base, modelName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		t.files[modelName] = f
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for i, d := range deltas {
+		if err := enc.Encode(tokenTiming{URL: url, TokenIndex: i, DeltaMS: float64(d) / float64(time.Millisecond)}); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Close closes every open token timing file and returns the first error.
+func (t *TokenLog) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var first error
+	for _, f := range t.files {
+		if err := f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	t.files = make(map[string]*os.File)
+	return first
+}
@@ -0,0 +1,49 @@
+/*
+PURPOSE:
+  Common contract for every per-result output format.
+  Lets the engine fan a result out to any set of writers without knowing their types.
+
+REQUIREMENTS:
+  User-specified:
+  - ResultWriter interface: Write(model.Result) error; Close() error.
+  - Adding a format should be a one-line registration in the runner.
+
+  Implementation-discovered:
+  - SummaryWriter consumes model.Aggregate, not model.Result, so it is not a ResultWriter.
+
+ARCHITECTURE INTEGRATION:
+  - Implemented by: CSVWriter, JSONWriter, SQLiteWriter
+  - Used by: internal/engine/runner.go
+
+ERROR HANDLING:
+  - Implementations return errors; the engine logs them and keeps running.
+
+IMPLEMENTATION RULES:
+  - Implementations must be thread-safe (URL and model workers write concurrently).
+  - Write must persist (flush) before returning, for crash resilience.
+
+USAGE:
+  var w output.ResultWriter
+  w, err = output.NewCSVWriter("results.csv", output.DefaultCSVOptions)
+
+SELF-HEALING INSTRUCTIONS:
+  - None.
+
+RELATED FILES:
+  - internal/output/csv.go
+  - internal/output/json.go
+  - internal/output/sqlite.go
+
+MAINTENANCE:
+  - New formats implement this interface and register with the Sink in engine.Run.
+*/
+
+package output
+
+import "github.com/daryltucker/forest-runner/internal/model"
+
+// ResultWriter persists individual benchmark results.
+type ResultWriter interface {
+	Write(r model.Result) error
+	Close() error
+}